@@ -0,0 +1,175 @@
+package dipper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	syncMapType     = reflect.TypeOf(sync.Map{})
+	atomicValueType = reflect.TypeOf(atomic.Value{})
+)
+
+// builtinResolvers contains the Resolvers used for standard library types.
+// They are used unless a different Resolver is registered for the same type.
+var builtinResolvers = map[reflect.Type]Resolver{
+	syncMapType:                syncMapResolver{},
+	reflect.PtrTo(syncMapType): syncMapResolver{},
+}
+
+// syncMapResolver is the Resolver used for sync.Map values. Keys are looked up
+// as strings first, and then as integers, floats and booleans (as in
+// map[interface{}]). New keys are stored as strings.
+type syncMapResolver struct{}
+
+// syncMap returns the *sync.Map of the given value, which must be a *sync.Map
+// or an addressable sync.Map.
+func (syncMapResolver) syncMap(value reflect.Value) (*sync.Map, error) {
+	if value.Kind() != reflect.Ptr {
+		if !value.CanAddr() {
+			return nil, ErrUnaddressable
+		}
+		value = value.Addr()
+	}
+	return value.Interface().(*sync.Map), nil
+}
+
+// find returns the key of the given *sync.Map accessed by name, its value and
+// true if the key exists.
+func (syncMapResolver) find(m *sync.Map, name string) (interface{}, interface{}, bool) {
+	for _, key := range interfaceMapKeys(name) {
+		if v, ok := m.Load(key.Interface()); ok {
+			return key.Interface(), v, true
+		}
+	}
+	return name, nil, false
+}
+
+func (r syncMapResolver) Get(value reflect.Value, name string) (reflect.Value, error) {
+	m, err := r.syncMap(value)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	_, v, ok := r.find(m, name)
+	if !ok {
+		return reflect.Value{}, ErrNotFound
+	}
+	return reflect.ValueOf(&v).Elem(), nil
+}
+
+func (r syncMapResolver) Set(value reflect.Value, name string, newValue interface{}) error {
+	m, err := r.syncMap(value)
+	if err != nil {
+		return err
+	}
+
+	key, _, _ := r.find(m, name)
+	if newValue == Zero || newValue == Delete {
+		m.Delete(key)
+	} else {
+		m.Store(key, newValue)
+	}
+	return nil
+}
+
+func (r syncMapResolver) Keys(value reflect.Value) ([]string, error) {
+	m, err := r.syncMap(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	m.Range(func(key, _ interface{}) bool {
+		keys = append(keys, fmt.Sprint(key))
+		return true
+	})
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// atomicValue returns the *atomic.Value of the given value and true if it is a
+// non-nil *atomic.Value or an atomic.Value. It returns ErrUnaddressable if the
+// atomic.Value is not addressable.
+func atomicValue(value reflect.Value) (*atomic.Value, bool, error) {
+	if value.Kind() == reflect.Ptr {
+		if value.Type().Elem() != atomicValueType || value.IsNil() {
+			return nil, false, nil
+		}
+		return value.Interface().(*atomic.Value), true, nil
+	}
+
+	if value.Type() != atomicValueType {
+		return nil, false, nil
+	}
+	if !value.CanAddr() {
+		return nil, true, ErrUnaddressable
+	}
+	return value.Addr().Interface().(*atomic.Value), true, nil
+}
+
+// loadAtomicValue returns the value stored in the given value if it is an
+// atomic.Value, otherwise it returns the given value.
+func loadAtomicValue(value reflect.Value) (reflect.Value, error) {
+	if !value.IsValid() || !value.CanInterface() {
+		return value, nil
+	}
+
+	av, ok, err := atomicValue(value)
+	if !ok || err != nil {
+		return value, err
+	}
+
+	v := av.Load()
+	return reflect.ValueOf(&v).Elem(), nil
+}
+
+// loadAtomicValueToSet works like loadAtomicValue, but the stored value is
+// returned as an addressable copy, which is stored back into the atomic.Value
+// by a commit added to the target once it has been modified.
+func loadAtomicValueToSet(value reflect.Value, target *setTarget) (reflect.Value, error) {
+	if !value.IsValid() || !value.CanInterface() {
+		return value, nil
+	}
+
+	av, ok, err := atomicValue(value)
+	if !ok || err != nil {
+		return value, err
+	}
+
+	v := av.Load()
+	loaded := reflect.ValueOf(&v).Elem()
+	c, ok := settableCopy(loaded)
+	if !ok {
+		return loaded, nil
+	}
+	target.commits = append(target.commits, func() error {
+		av.Store(c.Interface())
+		return nil
+	})
+	return c, nil
+}
+
+// storeAtomicValue stores the new value in the given atomic.Value. The new value
+// must have the same type as the currently stored value, if any. If the new
+// value is Zero or Delete, the zero value of the currently stored type is
+// stored.
+func storeAtomicValue(av *atomic.Value, newValue interface{}) error {
+	current := av.Load()
+
+	if newValue == Zero || newValue == Delete {
+		if current != nil {
+			av.Store(reflect.Zero(reflect.TypeOf(current)).Interface())
+		}
+		return nil
+	}
+
+	if newValue == nil || (current != nil && reflect.TypeOf(current) != reflect.TypeOf(newValue)) {
+		return ErrTypesDoNotMatch
+	}
+	av.Store(newValue)
+	return nil
+}
@@ -0,0 +1,221 @@
+package dipper_test
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+type Settings struct {
+	Timeout int
+	Labels  map[string]string
+}
+
+type RuntimeState struct {
+	Sessions *sync.Map
+	Counters sync.Map
+	Current  atomic.Value
+	Previous *atomic.Value
+	Empty    atomic.Value
+}
+
+func getTestRuntimeState() *RuntimeState {
+	state := &RuntimeState{
+		Sessions: &sync.Map{},
+		Previous: &atomic.Value{},
+	}
+	state.Sessions.Store("abc", Author{Name: "Umberto Eco"})
+	state.Sessions.Store(42, "answer")
+	state.Counters.Store("requests", 10)
+	state.Current.Store(Settings{Timeout: 30, Labels: map[string]string{"env": "prod"}})
+	state.Previous.Store(&Settings{Timeout: 10})
+	return state
+}
+
+func TestDipper_GetWithBuiltinResolvers(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "sync.Map pointer",
+			obj:       getTestRuntimeState(),
+			attribute: "Sessions.abc.Name",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "sync.Map with int key",
+			obj:       getTestRuntimeState(),
+			attribute: "Sessions.42",
+			want:      "answer",
+		},
+		{
+			name:      "sync.Map value",
+			obj:       getTestRuntimeState(),
+			attribute: "Counters.requests",
+			want:      10,
+		},
+		{
+			name:      "sync.Map key not found",
+			obj:       getTestRuntimeState(),
+			attribute: "Counters.errors",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "unaddressable sync.Map",
+			obj:       map[string]interface{}{"m": getTestRuntimeState().Sessions},
+			attribute: "m.abc.Name",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "atomic.Value",
+			obj:       getTestRuntimeState(),
+			attribute: "Current",
+			want:      Settings{Timeout: 30, Labels: map[string]string{"env": "prod"}},
+		},
+		{
+			name:      "atomic.Value nested field",
+			obj:       getTestRuntimeState(),
+			attribute: "Current.Labels.env",
+			want:      "prod",
+		},
+		{
+			name:      "atomic.Value pointer",
+			obj:       getTestRuntimeState(),
+			attribute: "Previous.Timeout",
+			want:      10,
+		},
+		{
+			name:      "empty atomic.Value",
+			obj:       getTestRuntimeState(),
+			attribute: "Empty",
+			want:      nil,
+		},
+		{
+			name:      "field of empty atomic.Value",
+			obj:       getTestRuntimeState(),
+			attribute: "Empty.Timeout",
+			want:      dipper.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dipper.Get(tt.obj, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_SetWithBuiltinResolvers(t *testing.T) {
+	tests := []struct {
+		name      string
+		attribute string
+		newValue  interface{}
+		wantErr   error
+		want      interface{}
+	}{
+		{
+			name:      "sync.Map existing key",
+			attribute: "Counters.requests",
+			newValue:  11,
+			want:      11,
+		},
+		{
+			name:      "sync.Map existing int key",
+			attribute: "Sessions.42",
+			newValue:  "question",
+			want:      "question",
+		},
+		{
+			name:      "sync.Map new key",
+			attribute: "Sessions.xyz",
+			newValue:  "new session",
+			want:      "new session",
+		},
+		{
+			name:      "sync.Map delete",
+			attribute: "Sessions.abc",
+			newValue:  dipper.Delete,
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "atomic.Value",
+			attribute: "Current",
+			newValue:  Settings{Timeout: 60},
+			want:      Settings{Timeout: 60},
+		},
+		{
+			name:      "empty atomic.Value",
+			attribute: "Empty",
+			newValue:  "foo",
+			want:      "foo",
+		},
+		{
+			name:      "atomic.Value with different type",
+			attribute: "Current",
+			newValue:  &Settings{Timeout: 60},
+			wantErr:   dipper.ErrTypesDoNotMatch,
+		},
+		{
+			name:      "atomic.Value zero",
+			attribute: "Current",
+			newValue:  dipper.Zero,
+			want:      Settings{},
+		},
+		{
+			name:      "field of pointer in atomic.Value",
+			attribute: "Previous.Timeout",
+			newValue:  20,
+			want:      20,
+		},
+		{
+			name:      "field of struct in atomic.Value",
+			attribute: "Current.Timeout",
+			newValue:  60,
+			want:      60,
+		},
+		{
+			name:      "map key of struct in atomic.Value",
+			attribute: "Current.Labels.env",
+			newValue:  "dev",
+			want:      "dev",
+		},
+		{
+			name:      "field of struct in sync.Map",
+			attribute: "Sessions.abc.Name",
+			newValue:  "Italo Calvino",
+			want:      "Italo Calvino",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := getTestRuntimeState()
+			err := dipper.Set(state, tt.attribute, tt.newValue)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("Set() = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if got := dipper.Get(state, tt.attribute); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Set() => Get() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDipper_GetUnaddressableSyncMap(t *testing.T) {
+	type Wrapper struct {
+		Counters sync.Map
+	}
+	got := dipper.Get(map[string]*Wrapper{"w": {}}, "w.Counters.foo")
+	if got != dipper.ErrNotFound {
+		t.Errorf("Get() = %v, want %v", got, dipper.ErrNotFound)
+	}
+}
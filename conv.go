@@ -1,5 +1,10 @@
 package dipper
 
+import (
+	"reflect"
+	"time"
+)
+
 // This file contains convenience functions using a default Dipper instance
 // prepared for dot notation.
 
@@ -15,7 +20,8 @@ type Fields map[string]interface{}
 // slice elements or map keys. Field names and key maps are case-sensitive.
 // All the struct fields accessed must be exported.
 // If an error occurs, it will be returned as the attribute value, so it should
-// be handled. All the returned errors are fieldError.
+// be handled. All the returned errors are fieldError, or *PathError if a
+// method called in the attribute returns an error.
 //
 // Example:
 //
@@ -49,7 +55,7 @@ func GetMany(obj interface{}, attributes []string) Fields {
 // All the struct fields accessed must be exported.
 // ErrUnaddressable will be returned if obj is not addressable.
 // It returns nil if the value was successfully set, otherwise it will return
-// a fieldError.
+// a fieldError, or a *PathError if a custom container returns another error.
 //
 // Example:
 //
@@ -60,3 +66,310 @@ func GetMany(obj interface{}, attributes []string) Fields {
 func Set(obj interface{}, attribute string, new interface{}) error {
 	return defaultDipper.Set(obj, attribute, new)
 }
+
+// GetJSON uses a default Dipper instance to return the value of the given
+// attribute in the JSON document data, without unmarshalling the whole
+// document. See Dipper.GetJSON() for more details.
+//
+// Example:
+//
+//	v := GetJSON(body, "data.user.id")
+//	if err := Error(v); err != nil {
+//	    return err
+//	}
+func GetJSON(data []byte, attribute string) interface{} {
+	return defaultDipper.GetJSON(data, attribute)
+}
+
+// SetJSON uses a default Dipper instance to set the value of the given
+// attribute in the JSON document data, keeping the formatting of the rest of
+// the document. See Dipper.SetJSON() for more details.
+//
+// Example:
+//
+//	config, err := SetJSON(config, "server.port", 8080)
+//	if err != nil {
+//	    return err
+//	}
+func SetJSON(data []byte, attribute string, value interface{}) ([]byte, error) {
+	return defaultDipper.SetJSON(data, attribute, value)
+}
+
+// DeleteJSON uses a default Dipper instance to delete the given attribute from
+// the JSON document data, keeping the formatting of the rest of the document.
+// See Dipper.DeleteJSON() for more details.
+//
+// Example:
+//
+//	config, err := DeleteJSON(config, "server.debug")
+//	if err != nil {
+//	    return err
+//	}
+func DeleteJSON(data []byte, attribute string) ([]byte, error) {
+	return defaultDipper.DeleteJSON(data, attribute)
+}
+
+// ApplyPatch uses a default Dipper instance to apply the given JSON Patch
+// operations to obj. If any operation fails, obj is left unchanged. See
+// Dipper.ApplyPatch() for more details.
+//
+// Example:
+//
+//	err := ApplyPatch(&book, []PatchOp{{Op: "replace", Path: "/Title", Value: "Dune"}})
+//	if err != nil {
+//	    return err
+//	}
+func ApplyPatch(obj interface{}, ops []PatchOp) error {
+	return defaultDipper.ApplyPatch(obj, ops)
+}
+
+// Merge uses a default Dipper instance to deep-merge src into dst. See
+// Dipper.Merge() for more details.
+//
+// Example:
+//
+//	err := Merge(&config, userConfig, MergeOptions{Slices: SliceAppend})
+//	if err != nil {
+//	    return err
+//	}
+func Merge(dst, src interface{}, opts MergeOptions) error {
+	return defaultDipper.Merge(dst, src, opts)
+}
+
+// MergePatch uses a default Dipper instance to apply the JSON Merge Patch
+// document patch to dst. See Dipper.MergePatch() for more details.
+//
+// Example:
+//
+//	err := MergePatch(&book, []byte(`{"Title": "Dune", "Extra": null}`))
+//	if err != nil {
+//	    return err
+//	}
+func MergePatch(dst interface{}, patch []byte) error {
+	return defaultDipper.MergePatch(dst, patch)
+}
+
+// Diff uses a default Dipper instance to return the differences between the
+// objects a (old) and b (new). See Dipper.Diff() for more details.
+//
+// Example:
+//
+//	changes := Diff(oldBook, newBook, "Genres[Name]")
+func Diff(a, b interface{}, keys ...string) []Change {
+	return defaultDipper.Diff(a, b, keys...)
+}
+
+// Flatten uses a default Dipper instance to return a map with the leaf values
+// of obj, using their attributes as keys. See Dipper.Flatten() for more
+// details.
+//
+// Example:
+//
+//	values := Flatten(config) // {"Server.Port": 8080, ...}
+func Flatten(obj interface{}) map[string]interface{} {
+	return defaultDipper.Flatten(obj)
+}
+
+// Unflatten uses a default Dipper instance to set the values of the given map,
+// whose keys are attributes, into target. See Dipper.Unflatten() for more
+// details.
+//
+// Example:
+//
+//	err := Unflatten(map[string]interface{}{"Server.Port": "8080"}, &config)
+//	if err != nil {
+//	    return err
+//	}
+func Unflatten(m map[string]interface{}, target interface{}) error {
+	return defaultDipper.Unflatten(m, target)
+}
+
+// Walk uses a default Dipper instance to call fn for obj and every value
+// reachable from it. See Dipper.Walk() for more details.
+//
+// Example:
+//
+//	Walk(config, func(path string, v reflect.Value, depth int) WalkAction {
+//	    fmt.Println(path, v)
+//	    return Continue
+//	})
+func Walk(obj interface{}, fn WalkFunc, opts ...WalkOptions) {
+	defaultDipper.Walk(obj, fn, opts...)
+}
+
+// PathsOf uses a default Dipper instance to return every attribute exposed by
+// the given type. See Dipper.PathsOf() for more details.
+//
+// Example:
+//
+//	paths := PathsOf(reflect.TypeOf(Config{}))
+func PathsOf(t reflect.Type) []PathInfo {
+	return defaultDipper.PathsOf(t)
+}
+
+// Validate uses a default Dipper instance to check that the given attribute
+// can resolve against values of type t. See Dipper.Validate() for more
+// details.
+//
+// Example:
+//
+//	err := Validate(reflect.TypeOf(Config{}), "Server.Port")
+//	if err != nil {
+//	    return err
+//	}
+func Validate(t reflect.Type, attribute string) error {
+	return defaultDipper.Validate(t, attribute)
+}
+
+// ValidateSet uses a default Dipper instance to check that a value of type
+// valueType can be set to the given attribute of values of type t. See
+// Dipper.ValidateSet() for more details.
+//
+// Example:
+//
+//	err := ValidateSet(reflect.TypeOf(&Config{}), "Server.Port", reflect.TypeOf(0))
+//	if err != nil {
+//	    return err
+//	}
+func ValidateSet(t reflect.Type, attribute string, valueType reflect.Type) error {
+	return defaultDipper.ValidateSet(t, attribute, valueType)
+}
+
+// GetString uses a default Dipper instance to return the value of the given
+// obj attribute as a string. See Dipper.GetString() for more details.
+//
+// Example:
+//
+//	name, err := GetString(config, "Server.Name")
+func GetString(obj interface{}, attribute string) (string, error) {
+	return defaultDipper.GetString(obj, attribute)
+}
+
+// GetInt uses a default Dipper instance to return the value of the given obj
+// attribute as an int. See Dipper.GetInt() for more details.
+//
+// Example:
+//
+//	port, err := GetInt(config, "Server.Port")
+func GetInt(obj interface{}, attribute string) (int, error) {
+	return defaultDipper.GetInt(obj, attribute)
+}
+
+// GetBool uses a default Dipper instance to return the value of the given obj
+// attribute as a bool. See Dipper.GetBool() for more details.
+//
+// Example:
+//
+//	enabled, err := GetBool(config, "Server.TLS.Enabled")
+func GetBool(obj interface{}, attribute string) (bool, error) {
+	return defaultDipper.GetBool(obj, attribute)
+}
+
+// GetDuration uses a default Dipper instance to return the value of the given
+// obj attribute as a time.Duration. See Dipper.GetDuration() for more details.
+//
+// Example:
+//
+//	timeout, err := GetDuration(config, "Server.Timeout")
+func GetDuration(obj interface{}, attribute string) (time.Duration, error) {
+	return defaultDipper.GetDuration(obj, attribute)
+}
+
+// GetTime uses a default Dipper instance to return the value of the given obj
+// attribute as a time.Time. See Dipper.GetTime() for more details.
+//
+// Example:
+//
+//	published, err := GetTime(book, "Publication.Date")
+func GetTime(obj interface{}, attribute string) (time.Time, error) {
+	return defaultDipper.GetTime(obj, attribute)
+}
+
+// GetInto uses a default Dipper instance to decode the value of the given obj
+// attribute into target. See Dipper.GetInto() for more details.
+//
+// Example:
+//
+//	var server ServerConfig
+//	err := GetInto(config, "Services.api.Server", &server)
+func GetInto(obj interface{}, attribute string, target interface{}) error {
+	return defaultDipper.GetInto(obj, attribute, target)
+}
+
+// ToMap uses a default Dipper instance to convert obj to nested maps. See
+// Dipper.ToMap() for more details.
+//
+// Example:
+//
+//	m := ToMap(book) // {"Title": "Dune", "Author": {"Name": ...}, ...}
+func ToMap(obj interface{}) map[string]interface{} {
+	return defaultDipper.ToMap(obj)
+}
+
+// FromMap uses a default Dipper instance to set the values of the given nested
+// maps into target. See Dipper.FromMap() for more details.
+//
+// Example:
+//
+//	var book Book
+//	err := FromMap(m, &book)
+//	if err != nil {
+//	    return err
+//	}
+func FromMap(m map[string]interface{}, target interface{}) error {
+	return defaultDipper.FromMap(m, target)
+}
+
+// Transform uses a default Dipper instance to build a new nested map from obj,
+// as described by the given spec. See Dipper.Transform() for more details.
+//
+// Example:
+//
+//	result, err := Transform(library, map[string]interface{}{"years": "Books[*].Year"})
+func Transform(obj interface{}, spec map[string]interface{}) (map[string]interface{}, error) {
+	return defaultDipper.Transform(obj, spec)
+}
+
+// TransformInto uses a default Dipper instance to build a new nested map from
+// obj, as described by the given spec, and set its values into target. See
+// Dipper.TransformInto() for more details.
+//
+// Example:
+//
+//	err := TransformInto(library, map[string]interface{}{"Years": "Books[*].Year"}, &summary)
+func TransformInto(obj interface{}, spec map[string]interface{}, target interface{}) error {
+	return defaultDipper.TransformInto(obj, spec, target)
+}
+
+// NewMapper uses a default Dipper instance to return a Mapper that copies
+// values from objects of type src into objects of type dst, following the
+// given mappings. See Dipper.NewMapper() for more details.
+//
+// Example:
+//
+//	mapper, err := NewMapper(reflect.TypeOf(UserDTO{}), reflect.TypeOf(User{}), []Mapping{{From: "Name", To: "Profile.DisplayName"}})
+func NewMapper(src, dst reflect.Type, mappings []Mapping) (*Mapper, error) {
+	return defaultDipper.NewMapper(src, dst, mappings)
+}
+
+// Pick uses a default Dipper instance to return a deep copy of obj with only
+// the values referenced by the given attributes. See Dipper.Pick() for more
+// details.
+//
+// Example:
+//
+//	picked, err := Pick(library, "Name", "Books[*].Title")
+func Pick(obj interface{}, attributes ...string) (interface{}, error) {
+	return defaultDipper.Pick(obj, attributes...)
+}
+
+// Omit uses a default Dipper instance to return a deep copy of obj without the
+// values referenced by the given attributes. See Dipper.Omit() for more
+// details.
+//
+// Example:
+//
+//	safe, err := Omit(user, "Password", "Sessions[*].Token")
+func Omit(obj interface{}, attributes ...string) (interface{}, error) {
+	return defaultDipper.Omit(obj, attributes...)
+}
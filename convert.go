@@ -0,0 +1,69 @@
+package dipper
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+// isNumberKind returns true if the given kind is an integer or float kind.
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// convertNumber converts the given numeric value to the numeric type t. It
+// returns false if the value or the type are not numeric, or if the value
+// cannot be represented exactly by t (e.g. 2.5 or -1 as an uint). A
+// json.Number is converted as the number it holds.
+func convertNumber(value reflect.Value, t reflect.Type) (reflect.Value, bool) {
+	if value.IsValid() && value.Type() == jsonNumberType {
+		value = parseJSONNumber(json.Number(value.String()))
+	}
+	if !isNumberKind(value.Kind()) || !isNumberKind(t.Kind()) {
+		return reflect.Value{}, false
+	}
+
+	negative := false
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		negative = value.Int() < 0
+	case reflect.Float32, reflect.Float64:
+		negative = value.Float() < 0
+	}
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if negative {
+			return reflect.Value{}, false
+		}
+	}
+
+	converted := value.Convert(t)
+	if converted.Convert(value.Type()).Interface() != value.Interface() {
+		return reflect.Value{}, false
+	}
+	return converted, true
+}
+
+// parseJSONNumber returns the value of the given JSON number as an int64, an
+// uint64 or a float64, using the first type that can hold it, or an invalid
+// reflect.Value if it is not a number.
+func parseJSONNumber(n json.Number) reflect.Value {
+	if i, err := n.Int64(); err == nil {
+		return reflect.ValueOf(i)
+	}
+	if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil {
+		return reflect.ValueOf(u)
+	}
+	if f, err := n.Float64(); err == nil {
+		return reflect.ValueOf(f)
+	}
+	return reflect.Value{}
+}
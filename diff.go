@@ -0,0 +1,358 @@
+package dipper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ChangeOp is the operation of a Change.
+type ChangeOp string
+
+// Change operations.
+const (
+	// ChangeAdded is the operation of a value that only exists in the new
+	// object (e.g. a new map key or slice element).
+	ChangeAdded ChangeOp = "added"
+	// ChangeRemoved is the operation of a value that only exists in the old
+	// object.
+	ChangeRemoved ChangeOp = "removed"
+	// ChangeModified is the operation of a value that exists in both objects
+	// with different values.
+	ChangeModified ChangeOp = "modified"
+)
+
+// Change is a difference between two objects found by Dipper.Diff().
+type Change struct {
+	// Path is the attribute of the changed value, using the Dipper syntax.
+	Path string `json:"path"`
+	// Pointer is the JSON Pointer of the changed value, used to convert the
+	// change to a JSON Patch operation (see ToPatch()).
+	Pointer string `json:"pointer"`
+	// Op is the change operation.
+	Op ChangeOp `json:"op"`
+	// Old is the value in the old object, or nil if the value was added.
+	Old interface{} `json:"old,omitempty"`
+	// New is the value in the new object, or nil if the value was removed.
+	New interface{} `json:"new,omitempty"`
+}
+
+// Diff returns the differences between the objects a (old) and b (new),
+// recursing through structs, maps and slices. Struct fields are named as they
+// are accessed in attributes (e.g. according to the TagName option). Values of
+// different types are reported as modified. Raw JSON documents are compared by
+// their decoded values, and other byte slices as a whole.
+// By default, slice elements are compared by index. The keys argument allows
+// to match the elements of some slices by a key attribute, using the format
+// "<slice attribute>[<key attribute>]", where "*" can be used as any slice
+// index or map key in the slice attribute (e.g. "Books[Title]" or
+// "Authors.*.Books[Title]"). The path of the matched elements uses a filter
+// expression if the key is a string, number or boolean (e.g.
+// "Books[Title='Dune'].Year"), and the elements order is ignored.
+// Changes are sorted so they can be applied in order as JSON Patch operations:
+// for every slice, modified elements go first, then the removed elements (from
+// the last one), and then the added elements.
+//
+// Example:
+//
+//	for _, c := range my_dipper.Diff(oldBook, newBook, "Genres[Name]") {
+//	    log.Printf("%s %s: %v -> %v", c.Op, c.Path, c.Old, c.New)
+//	}
+func (d *Dipper) Diff(a, b interface{}, keys ...string) []Change {
+	df := differ{d: d, keys: map[string]string{}, visited: map[[2]uintptr]bool{}}
+	for _, k := range keys {
+		if i := strings.LastIndexByte(k, '['); i >= 0 && strings.HasSuffix(k, "]") {
+			df.keys[k[:i]] = k[i+1 : len(k)-1]
+		}
+	}
+
+	df.diff(diffPath{}, reflect.ValueOf(a), reflect.ValueOf(b))
+	return df.changes
+}
+
+// ToPatch converts the given changes to JSON Patch operations, using their
+// JSON Pointers.
+func ToPatch(changes []Change) []PatchOp {
+	ops := make([]PatchOp, 0, len(changes))
+	for _, c := range changes {
+		op := PatchOp{Path: c.Pointer, Value: c.New}
+		switch c.Op {
+		case ChangeAdded:
+			op.Op = "add"
+		case ChangeRemoved:
+			op.Op = "remove"
+			op.Value = nil
+		default:
+			op.Op = "replace"
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// diffPath is the path of a value compared by differ.
+type diffPath struct {
+	attribute string
+	pointer   string
+	// fields are the fields of the path, used to match the slice keys
+	fields []string
+}
+
+// child returns the path of the child with the given name.
+func (p diffPath) child(d *Dipper, name string) diffPath {
+	fields := make([]string, len(p.fields)+1)
+	copy(fields, p.fields)
+	fields[len(p.fields)] = name
+
+	return diffPath{
+		attribute: d.joinAttribute(p.attribute, name),
+		pointer:   p.pointer + "/" + escapePointer(name),
+		fields:    fields,
+	}
+}
+
+// element returns the path of the slice element with the given key attribute
+// and value, or the given index if they cannot be used in a filter expression.
+// pointerIndex is the index or field used in the JSON Pointer.
+func (p diffPath) element(d *Dipper, keyAttribute string, key reflect.Value, index int, pointerIndex string) diffPath {
+	elem := p.child(d, strconv.Itoa(index))
+	elem.pointer = p.pointer + "/" + pointerIndex
+
+	if value, ok := filterValue(key); ok && d.opts.Syntax != JSONPointer {
+		if filter := keyAttribute + "=" + value; filterRegex.MatchString(filter) {
+			elem.attribute = p.attribute + "[" + filter + "]"
+		}
+	}
+	return elem
+}
+
+// differ finds the differences between two objects.
+type differ struct {
+	d *Dipper
+	// keys are the key attributes of the slices matched by key, by slice
+	// attribute
+	keys    map[string]string
+	visited map[[2]uintptr]bool
+	changes []Change
+}
+
+// add adds a change to the result.
+func (df *differ) add(path diffPath, op ChangeOp, a, b reflect.Value) {
+	c := Change{Path: path.attribute, Pointer: path.pointer, Op: op}
+	if a.IsValid() && a.CanInterface() {
+		c.Old = a.Interface()
+	}
+	if b.IsValid() && b.CanInterface() {
+		c.New = b.Interface()
+	}
+	df.changes = append(df.changes, c)
+}
+
+// diff adds the differences between a and b to the result.
+func (df *differ) diff(path diffPath, a, b reflect.Value) {
+	a, b = interfaceElem(a), interfaceElem(b)
+	if a.Kind() == reflect.Ptr && b.Kind() == reflect.Ptr && !a.IsNil() && !b.IsNil() {
+		pair := [2]uintptr{a.Pointer(), b.Pointer()}
+		if a.Pointer() == b.Pointer() || df.visited[pair] {
+			return
+		}
+		df.visited[pair] = true
+	}
+
+	// Pointers are compared by the values they point to, but the changes
+	// report the values as they are in the objects
+	oldValue, newValue := a, b
+	a, b = getElemSafe(a), getElemSafe(b)
+	aNil := !a.IsValid() || (a.Kind() == reflect.Ptr && a.IsNil())
+	bNil := !b.IsValid() || (b.Kind() == reflect.Ptr && b.IsNil())
+
+	switch {
+	case aNil && bNil:
+		return
+	case aNil || bNil || a.Type() != b.Type():
+		df.add(path, ChangeModified, oldValue, newValue)
+		return
+	}
+
+	if rawA, rawB := df.d.getRawJSON(a), df.d.getRawJSON(b); rawA.IsValid() && rawB.IsValid() {
+		// Raw JSON documents are compared by their decoded values, so only
+		// the values that changed are reported
+		docA, errA := decodeRawJSON(rawA, "")
+		docB, errB := decodeRawJSON(rawB, "")
+		if errA == nil && errB == nil {
+			df.diff(path, docA, docB)
+			return
+		}
+	}
+
+	switch a.Kind() {
+	case reflect.Struct:
+		if !hasExportedFields(a.Type()) {
+			break
+		}
+		bFields := map[string]reflect.Value{}
+		for _, f := range df.d.structFieldValues(b) {
+			bFields[f.name] = f.value
+		}
+		for _, f := range df.d.structFieldValues(a) {
+			df.diff(path.child(df.d, f.name), f.value, bFields[f.name])
+		}
+		return
+
+	case reflect.Map:
+		df.diffMaps(path, a, b)
+		return
+
+	case reflect.Slice, reflect.Array:
+		if a.Type().Elem().Kind() == reflect.Uint8 {
+			// Byte slices are compared as a whole
+			break
+		}
+		if key, ok := df.sliceKey(path); ok {
+			df.diffSlicesByKey(path, a, b, key)
+		} else {
+			df.diffSlices(path, a, b)
+		}
+		return
+	}
+
+	if !a.CanInterface() || !b.CanInterface() || !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		df.add(path, ChangeModified, oldValue, newValue)
+	}
+}
+
+// diffMaps adds the differences between the maps a and b to the result.
+func (df *differ) diffMaps(path diffPath, a, b reflect.Value) {
+	keys := sortedMapKeys(a)
+	for _, key := range sortedMapKeys(b) {
+		if !a.MapIndex(key).IsValid() {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		keyPath := path.child(df.d, fmt.Sprint(key.Interface()))
+		aValue, bValue := a.MapIndex(key), b.MapIndex(key)
+		switch {
+		case !bValue.IsValid():
+			df.add(keyPath, ChangeRemoved, aValue, bValue)
+		case !aValue.IsValid():
+			df.add(keyPath, ChangeAdded, aValue, bValue)
+		default:
+			df.diff(keyPath, aValue, bValue)
+		}
+	}
+}
+
+// diffSlices adds the differences between the slices a and b to the result,
+// comparing their elements by index.
+func (df *differ) diffSlices(path diffPath, a, b reflect.Value) {
+	n := a.Len()
+	if b.Len() < n {
+		n = b.Len()
+	}
+
+	for i := 0; i < n; i++ {
+		df.diff(path.child(df.d, strconv.Itoa(i)), a.Index(i), b.Index(i))
+	}
+	for i := a.Len() - 1; i >= n; i-- {
+		df.add(path.child(df.d, strconv.Itoa(i)), ChangeRemoved, a.Index(i), reflect.Value{})
+	}
+	for i := n; i < b.Len(); i++ {
+		df.add(path.child(df.d, strconv.Itoa(i)), ChangeAdded, reflect.Value{}, b.Index(i))
+	}
+}
+
+// diffSlicesByKey adds the differences between the slices a and b to the
+// result, matching their elements by the given key attribute.
+func (df *differ) diffSlicesByKey(path diffPath, a, b reflect.Value, key string) {
+	keyOf := func(v reflect.Value) reflect.Value {
+		k := df.d.Get(v.Interface(), key)
+		if Error(k) != nil {
+			return reflect.Value{}
+		}
+		return reflect.ValueOf(k)
+	}
+	indexOf := func(s reflect.Value, k reflect.Value) int {
+		if !k.IsValid() {
+			return -1
+		}
+		for i := 0; i < s.Len(); i++ {
+			if other := keyOf(s.Index(i)); other.IsValid() && reflect.DeepEqual(k.Interface(), other.Interface()) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var removed []int
+	for i := 0; i < a.Len(); i++ {
+		k := keyOf(a.Index(i))
+		j := indexOf(b, k)
+		if j < 0 {
+			removed = append(removed, i)
+			continue
+		}
+		df.diff(path.element(df.d, key, k, i, strconv.Itoa(i)), a.Index(i), b.Index(j))
+	}
+	for r := len(removed) - 1; r >= 0; r-- {
+		i := removed[r]
+		df.add(path.element(df.d, key, keyOf(a.Index(i)), i, strconv.Itoa(i)), ChangeRemoved, a.Index(i), reflect.Value{})
+	}
+	for j := 0; j < b.Len(); j++ {
+		k := keyOf(b.Index(j))
+		if indexOf(a, k) < 0 {
+			df.add(path.element(df.d, key, k, j, "-"), ChangeAdded, reflect.Value{}, b.Index(j))
+		}
+	}
+}
+
+// sliceKey returns the key attribute of the slice with the given path, if it
+// is matched by key.
+func (df *differ) sliceKey(path diffPath) (string, bool) {
+	for pattern, key := range df.keys {
+		splitter, err := df.d.newSplitter(pattern)
+		if err != nil {
+			continue
+		}
+
+		matches := true
+		i := 0
+		for ; splitter.HasMore() && matches; i++ {
+			field, _ := splitter.Next()
+			matches = i < len(path.fields) && (field == "*" || field == path.fields[i])
+		}
+		if matches && i == len(path.fields) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// interfaceElem returns the value held by the given value while it is a
+// non-nil interface.
+func interfaceElem(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Interface && !value.IsNil() {
+		value = value.Elem()
+	}
+	return value
+}
+
+// filterValue returns the given key value written as a filter expression
+// value, and true if the key can be used in a filter expression.
+func filterValue(key reflect.Value) (string, bool) {
+	key = getElemSafe(key)
+	switch key.Kind() {
+	case reflect.String:
+		if strings.ContainsAny(key.String(), "'[]") {
+			return "", false
+		}
+		return "'" + key.String() + "'", true
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(key.Interface()), true
+	}
+	return "", false
+}
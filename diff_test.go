@@ -0,0 +1,181 @@
+package dipper_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestDipper_Diff(t *testing.T) {
+	newBook := func(modify func(b *Book)) *Book {
+		b := getTestStruct()
+		modify(b)
+		return b
+	}
+
+	tests := []struct {
+		name string
+		opts dipper.Options
+		a    interface{}
+		b    interface{}
+		keys []string
+		want []dipper.Change
+	}{
+		{
+			name: "equal",
+			a:    getTestStruct(),
+			b:    getTestStruct(),
+			want: nil,
+		},
+		{
+			name: "struct fields",
+			a:    getTestStruct(),
+			b: newBook(func(b *Book) {
+				b.Year = 1981
+				b.Author.Name = "U. Eco"
+				b.ISBN = "0987654321"
+			}),
+			want: []dipper.Change{
+				{Path: "Year", Pointer: "/Year", Op: dipper.ChangeModified, Old: 1980, New: 1981},
+				{Path: "Author.Name", Pointer: "/Author/Name", Op: dipper.ChangeModified, Old: "Umberto Eco", New: "U. Eco"},
+				{Path: "ISBN", Pointer: "/ISBN", Op: dipper.ChangeModified, Old: "1234567890", New: "0987654321"},
+			},
+		},
+		{
+			name: "tag names and separator",
+			opts: dipper.Options{TagName: "json", Separator: "/"},
+			a:    getTestStruct(),
+			b:    newBook(func(b *Book) { b.Genres[1].Name = "Thriller" }),
+			want: []dipper.Change{
+				{Path: "genres/1/name", Pointer: "/genres/1/name", Op: dipper.ChangeModified, Old: "Crime", New: "Thriller"},
+			},
+		},
+		{
+			name: "maps",
+			a:    map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "x"}, "d/e": true},
+			b:    map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": "y"}, "f": nil},
+			want: []dipper.Change{
+				{Path: "b.c", Pointer: "/b/c", Op: dipper.ChangeModified, Old: "x", New: "y"},
+				{Path: "d/e", Pointer: "/d~1e", Op: dipper.ChangeRemoved, Old: true},
+				{Path: "f", Pointer: "/f", Op: dipper.ChangeAdded},
+			},
+		},
+		{
+			name: "different types",
+			a:    map[string]interface{}{"a": 1, "b": nil},
+			b:    map[string]interface{}{"a": "1", "b": []int{}},
+			want: []dipper.Change{
+				{Path: "a", Pointer: "/a", Op: dipper.ChangeModified, Old: 1, New: "1"},
+				{Path: "b", Pointer: "/b", Op: dipper.ChangeModified, New: []int{}},
+			},
+		},
+		{
+			name: "slices by index",
+			a:    []string{"a", "b", "c", "d"},
+			b:    []string{"a", "x"},
+			want: []dipper.Change{
+				{Path: "1", Pointer: "/1", Op: dipper.ChangeModified, Old: "b", New: "x"},
+				{Path: "3", Pointer: "/3", Op: dipper.ChangeRemoved, Old: "d"},
+				{Path: "2", Pointer: "/2", Op: dipper.ChangeRemoved, Old: "c"},
+			},
+		},
+		{
+			name: "slices by key",
+			a:    getTestStruct(),
+			b: newBook(func(b *Book) {
+				b.Genres = []Genre{
+					{ID: 2, Name: "Historical"},
+					{ID: 1, Name: "Crime", Description: "Crime fiction"},
+				}
+			}),
+			keys: []string{"Genres[Name]"},
+			want: []dipper.Change{
+				{Path: "Genres[Name='Crime'].Description", Pointer: "/Genres/1/Description", Op: dipper.ChangeModified,
+					Old: getTestStruct().Genres[1].Description, New: "Crime fiction"},
+				{Path: "Genres[Name='Mystery']", Pointer: "/Genres/0", Op: dipper.ChangeRemoved, Old: getTestStruct().Genres[0]},
+				{Path: "Genres[Name='Historical']", Pointer: "/Genres/-", Op: dipper.ChangeAdded, New: Genre{ID: 2, Name: "Historical"}},
+			},
+		},
+		{
+			name: "nested slices by key with wildcard",
+			a: map[string]interface{}{"authors": []interface{}{
+				map[string]interface{}{"books": []interface{}{map[string]interface{}{"id": 1, "year": 1980}}},
+			}},
+			b: map[string]interface{}{"authors": []interface{}{
+				map[string]interface{}{"books": []interface{}{map[string]interface{}{"id": 1, "year": 1981}}},
+			}},
+			keys: []string{"authors.*.books[id]"},
+			want: []dipper.Change{
+				{Path: "authors.0.books[id=1].year", Pointer: "/authors/0/books/0/year", Op: dipper.ChangeModified, Old: 1980, New: 1981},
+			},
+		},
+		{
+			name: "JSON pointer syntax",
+			opts: dipper.Options{Syntax: dipper.JSONPointer},
+			a:    map[string]interface{}{"a.b": []int{1}},
+			b:    map[string]interface{}{"a.b": []int{2}},
+			keys: []string{"/a.b[]"},
+			want: []dipper.Change{
+				{Path: "/a.b/0", Pointer: "/a.b/0", Op: dipper.ChangeRemoved, Old: 1},
+				{Path: "/a.b/0", Pointer: "/a.b/-", Op: dipper.ChangeAdded, New: 2},
+			},
+		},
+		{
+			name: "raw JSON documents",
+			a:    &Event{Payload: json.RawMessage(`{"id": 7, "tags": ["a"]}`), Data: []byte("abc")},
+			b:    &Event{Payload: json.RawMessage(`{"id":8,"tags":["a"]}`), Data: []byte("abd")},
+			want: []dipper.Change{
+				{Path: "Payload.id", Pointer: "/Payload/id", Op: dipper.ChangeModified, Old: json.Number("7"), New: json.Number("8")},
+				{Path: "Data", Pointer: "/Data", Op: dipper.ChangeModified, Old: []byte("abc"), New: []byte("abd")},
+			},
+		},
+		{
+			name: "bytes with JSONBytes option",
+			opts: dipper.Options{JSONBytes: true},
+			a:    &Event{Data: []byte(`{"source": "api"}`)},
+			b:    &Event{Data: []byte(`{"source": "cli", "id": 1}`)},
+			want: []dipper.Change{
+				{Path: "Data.source", Pointer: "/Data/source", Op: dipper.ChangeModified, Old: "api", New: "cli"},
+				{Path: "Data.id", Pointer: "/Data/id", Op: dipper.ChangeAdded, New: json.Number("1")},
+			},
+		},
+		{
+			name: "nil pointer",
+			a:    &struct{ Author *Author }{},
+			b:    &struct{ Author *Author }{Author: &Author{Name: "Umberto Eco"}},
+			want: []dipper.Change{
+				{Path: "Author", Pointer: "/Author", Op: dipper.ChangeModified, Old: (*Author)(nil), New: &Author{Name: "Umberto Eco"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dipper.New(tt.opts).Diff(tt.a, tt.b, tt.keys...)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToPatch(t *testing.T) {
+	a := getTestStruct()
+	b := getTestStruct()
+	b.Title = "Il nome della rosa"
+	b.GenreNames = []string{"Mystery"}
+	b.Genres = append(b.Genres[1:], Genre{ID: 2, Name: "Historical"})
+	b.Extra["pages"] = 512
+	delete(b.Extra, "foo")
+
+	for _, keys := range [][]string{nil, {"Genres[ID]"}} {
+		got := getTestStruct()
+		if err := dipper.ApplyPatch(got, dipper.ToPatch(dipper.Diff(a, b, keys...))); err != nil {
+			t.Fatalf("ApplyPatch() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, b) {
+			t.Errorf("ApplyPatch(ToPatch()) = %v, want %v", got, b)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // setOption is a type used for special assignments in a set operation.
@@ -20,7 +21,52 @@ const (
 
 // Options defines the configuration of a Dipper instance.
 type Options struct {
+	// Separator is the delimiter used to split the attribute fields. The
+	// default separator is ".". It is not used with the JSONPointer syntax.
 	Separator string
+	// Syntax is the notation used to write attributes. The default syntax is
+	// DotNotation.
+	Syntax Syntax
+	// TagName is the name of the struct tag used to get the field names (e.g.
+	// "json"). If a field has no tag or the tag name is empty, the Go field
+	// name is used. Fields with the tag "-" cannot be accessed.
+	// If TagName is empty, only the Go field names are used.
+	TagName string
+	// CaseInsensitive allows to access struct fields, map keys and filter keys
+	// without regard to case. An exact match always takes precedence. If there
+	// is no exact match and several fields or keys match the name regardless
+	// of case, ErrAmbiguousField is returned.
+	CaseInsensitive bool
+	// AllowUnexported allows to get the value of unexported struct fields.
+	// Unexported fields are accessed using package unsafe, so this option
+	// should only be used for debugging, testing or similar purposes.
+	AllowUnexported bool
+	// AllowUnexportedSet allows to set the value of unexported struct fields.
+	// It has no effect unless AllowUnexported is also enabled.
+	AllowUnexportedSet bool
+	// DisableMethods disables method calls in attributes. By default, a field
+	// ending in "()" (e.g. "Author.FullName()") calls the exported method with
+	// that name, which must take no arguments and return a value and,
+	// optionally, an error. Method calls are only allowed in get operations.
+	// This option should be enabled when attributes come from untrusted
+	// sources.
+	DisableMethods bool
+	// Resolvers sets the Resolver used to access the children of values of a
+	// given type (e.g. custom containers such as *list.List). The Resolver is
+	// used for values of the registered type, or pointers to it.
+	Resolvers map[reflect.Type]Resolver
+	// JSONBytes allows to access the JSON document held by []byte values, as
+	// it is done with json.RawMessage values. The document is decoded to get
+	// its values, and encoded back into the original value when it is set.
+	JSONBytes bool
+	// ConvertNumbers allows the generic accessors (e.g. GetAs()) to convert
+	// numeric values to the requested numeric type (e.g. a float64 decoded
+	// from JSON to an int), as long as the value is preserved exactly.
+	ConvertNumbers bool
+	// TimeLayouts are the layouts used by Dipper.GetTime() to parse string
+	// values, in order of preference. The default layouts are time.RFC3339,
+	// "2006-01-02T15:04:05" and "2006-01-02".
+	TimeLayouts []string
 }
 
 // Dipper allows to access deeply-nested object attributes to get or set their
@@ -28,20 +74,29 @@ type Options struct {
 // some delimiter (e.g. “Books.3.Author" or "Books->3->Author", with "." and
 // "->" as delimiters, respectively).
 type Dipper struct {
-	separator string
+	opts Options
 }
 
 // New returns a new Dipper instance.
 func New(opts Options) *Dipper {
-	return &Dipper{separator: opts.Separator}
+	if opts.Separator == "" {
+		opts.Separator = "."
+	}
+	if len(opts.TimeLayouts) == 0 {
+		opts.TimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+	}
+	return &Dipper{opts: opts}
 }
 
 // Get returns the value of the given obj attribute. The attribute uses some
 // delimiter-notation to allow accessing nested fields, slice elements or map
-// keys. Field names and key maps are case-sensitive.
-// All the struct fields accessed must be exported.
+// keys. Field names and key maps are case-sensitive, unless the CaseInsensitive
+// option is enabled.
+// All the struct fields accessed must be exported, unless the AllowUnexported
+// option is enabled.
 // If an error occurs, it will be returned as the attribute value, so it should
-// be handled. All the returned errors are fieldError.
+// be handled. All the returned errors are fieldError, or *PathError if a
+// method called in the attribute returns an error.
 //
 // Example:
 //
@@ -51,7 +106,10 @@ func New(opts Options) *Dipper {
 //		    return err
 //		}
 func (d *Dipper) Get(obj interface{}, attribute string) interface{} {
-	value, _, err := getReflectValue(reflect.ValueOf(obj), attribute, d.separator, false)
+	value, err := d.getReflectValue(reflect.ValueOf(obj), attribute, nil)
+	if err == nil {
+		value, err = loadAtomicValue(value)
+	}
 	if err != nil {
 		return err
 	}
@@ -84,11 +142,15 @@ func (d *Dipper) GetMany(obj interface{}, attributes []string) Fields {
 
 // Set sets the value of the given obj attribute to the new provided value.
 // The attribute uses some delimiter-notation to allow accessing nested fields,
-// slice elements or map keys. Field names and key maps are case-sensitive.
-// All the struct fields accessed must be exported.
+// slice elements or map keys. Field names and key maps are case-sensitive,
+// unless the CaseInsensitive option is enabled.
+// All the struct fields accessed must be exported, unless the AllowUnexported
+// and AllowUnexportedSet options are enabled.
 // ErrUnaddressable will be returned if obj is not addressable.
+// Using the JSONPointer syntax, the "-" field appends the new value to a slice
+// (e.g. "/Books/-").
 // It returns nil if the value was successfully set, otherwise it will return
-// a fieldError.
+// a fieldError, or a *PathError if a custom container returns another error.
 //
 // Example:
 //
@@ -98,7 +160,13 @@ func (d *Dipper) GetMany(obj interface{}, attributes []string) Fields {
 //		    return err
 //		}
 func (d *Dipper) Set(obj interface{}, attribute string, new interface{}) error {
-	var err error
+	if d.opts.Syntax == JSONPointer {
+		if parent, ok := appendPointer(attribute); ok {
+			if isSlice, err := d.appendValue(obj, parent, new); isSlice || err != nil {
+				return err
+			}
+		}
+	}
 
 	value := reflect.ValueOf(obj)
 
@@ -106,12 +174,48 @@ func (d *Dipper) Set(obj interface{}, attribute string, new interface{}) error {
 		value = value.Elem()
 	}
 
-	var lastField string
-	value, lastField, err = getReflectValue(value, attribute, d.separator, true)
+	target := &setTarget{}
+	value, err := d.getReflectValue(value, attribute, target)
+	if err != nil {
+		return err
+	}
+
+	err = d.setValue(value, target, attribute, new)
 	if err != nil {
 		return err
 	}
 
+	// Apply the pending changes from the innermost to the outermost value
+	for i := len(target.commits) - 1; i >= 0; i-- {
+		if err := target.commits[i](); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTarget holds the information needed by a set operation, filled in by
+// getReflectValue.
+type setTarget struct {
+	// isChild is true if the last field is a map key or a custom container
+	// child. In that case, the resolved value is the map or container and
+	// fieldName is the name of the child.
+	isChild   bool
+	fieldName string
+	// commits are functions that must be called after setting the value to
+	// apply the changes to the original object (e.g. to encode a decoded JSON
+	// document back into its json.RawMessage field).
+	commits []func() error
+}
+
+// setValue sets the new value to the value resolved by getReflectValue.
+func (d *Dipper) setValue(value reflect.Value, target *setTarget, attribute string, new interface{}) error {
+	if target.isChild {
+		if r, container := d.getResolver(value); r != nil {
+			return pathError(r.Set(container, target.fieldName, new), attribute)
+		}
+	}
+
 	var optZero, optDelete bool
 
 	var newValue reflect.Value
@@ -120,14 +224,34 @@ func (d *Dipper) Set(obj interface{}, attribute string, new interface{}) error {
 		optZero = true
 	case Delete:
 		optDelete = true
+	case nil:
+		// nil can only be set to types that can hold it
+		switch t := setType(value, target); t.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			newValue = reflect.Zero(t)
+		default:
+			return ErrTypesDoNotMatch
+		}
 	default:
 		newValue = reflect.ValueOf(new)
-		if newValue.Kind() == reflect.Ptr {
+		if newValue.Kind() == reflect.Ptr && newValue.Type() != setType(value, target) {
 			newValue = newValue.Elem()
 		}
 	}
 
-	if value.Kind() == reflect.Map {
+	if av, ok, err := atomicValue(value); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		return storeAtomicValue(av, new)
+	}
+
+	if target.isChild && value.Kind() == reflect.Map {
+		key, _, err := d.findMapKey(value, target.fieldName)
+		if err != nil {
+			return err
+		}
+
 		if !optZero && !optDelete {
 			mapValueType := value.Type().Elem()
 			if mapValueType.Kind() != reflect.Interface && mapValueType != newValue.Type() {
@@ -143,7 +267,7 @@ func (d *Dipper) Set(obj interface{}, attribute string, new interface{}) error {
 			value.Set(reflect.MakeMapWithSize(mapType, 0))
 		}
 
-		value.SetMapIndex(reflect.ValueOf(lastField), newValue)
+		value.SetMapIndex(key, newValue)
 	} else {
 		if !optZero && !optDelete {
 			if !value.CanAddr() {
@@ -160,66 +284,172 @@ func (d *Dipper) Set(obj interface{}, attribute string, new interface{}) error {
 	return nil
 }
 
+// setType returns the type of the value to be set in the value resolved by
+// getReflectValue.
+func setType(value reflect.Value, target *setTarget) reflect.Type {
+	if target.isChild && value.Kind() == reflect.Map {
+		return value.Type().Elem()
+	}
+	return value.Type()
+}
+
 // getReflectValue gets the reflect.Value of the given value attribute.
 // It splits the attribute into the field names, map keys and slice indexes
 // and uses reflection to get the final value.
-// toSet indicates that the function must return a value that will be set to
-// another value, which is used in the special case of maps (maps elements are
-// not addressable).
-// It also returns the name of the accessed field.
-func getReflectValue(value reflect.Value, attribute string, sep string, toSet bool) (_ reflect.Value, fieldName string, _ error) {
+// If target is not nil, the function must return a value that will be set to
+// another value, and target is filled in with the information needed to set
+// it. This is used in the special case of maps (maps elements are not
+// addressable) and custom containers, which are returned instead of the child
+// value.
+func (d *Dipper) getReflectValue(value reflect.Value, attribute string, target *setTarget) (reflect.Value, error) {
 	if attribute == "" {
-		return value, "", nil
+		return value, nil
 	}
 
-	if len(sep) == 0 {
-		sep = "."
+	splitter, err := d.newSplitter(attribute)
+	if err != nil {
+		return value, err
 	}
 
-	splitter := newAttributeSplitter(attribute, sep)
+	toSet := target != nil
 
+	var fieldName string
 	var i, maxSetDepth int
 	if toSet {
 		maxSetDepth = splitter.CountRemaining() - 1
 	}
 
+	// The map and key of the current value, if it is a map value
+	var parentMap, parentKey reflect.Value
+
 	for splitter.HasMore() {
+		parentPath := splitter.Parsed()
 		fieldName, i = splitter.Next()
+
+		mapValue, mapKey := parentMap, parentKey
+		parentMap, parentKey = reflect.Value{}, reflect.Value{}
+
+		if d.opts.Syntax != JSONPointer && !d.opts.DisableMethods && isMethodCall(fieldName) {
+			if toSet {
+				if hasMethod(value, fieldName) {
+					return value, ErrMethodNotSettable
+				}
+			} else {
+				result, found, err := callMethod(value, fieldName, splitter.Parsed())
+				if err != nil {
+					return value, err
+				}
+				if found {
+					value = result
+					continue
+				}
+			}
+		}
+
+		if toSet {
+			value, err = loadAtomicValueToSet(value, target)
+		} else {
+			value, err = loadAtomicValue(value)
+		}
+		if err != nil {
+			return value, err
+		}
+
+		if raw := d.getRawJSON(value); raw.IsValid() {
+			doc, err := decodeRawJSON(raw, parentPath)
+			if err != nil {
+				return value, err
+			}
+			if toSet {
+				if !raw.CanSet() {
+					if !mapValue.IsValid() {
+						return value, ErrUnaddressable
+					}
+					// Map values are not addressable, so the document is
+					// encoded into a copy that is set back into the map
+					c := reflect.New(raw.Type()).Elem()
+					c.Set(raw)
+					raw = c
+					target.commits = append(target.commits, func() error {
+						mapValue.SetMapIndex(mapKey, c)
+						return nil
+					})
+				}
+				target.commits = append(target.commits, encodeRawJSON(raw, doc, parentPath))
+			}
+			value = doc
+		}
+
+		if r, container := d.getResolver(value); r != nil {
+			// If a child has to be set, skip the last attribute and return the container
+			if toSet && i == maxSetDepth {
+				target.isChild = true
+				target.fieldName = fieldName
+				return value, nil
+			}
+
+			child, err := r.Get(container, fieldName)
+			if err != nil {
+				return value, pathError(err, splitter.Parsed())
+			}
+
+			// The child is modified in a copy, which is set back once modified
+			if c, ok := settableCopy(child); toSet && ok {
+				name, path := fieldName, splitter.Parsed()
+				target.commits = append(target.commits, func() error {
+					return pathError(r.Set(container, name, c.Interface()), path)
+				})
+				child = c
+			}
+
+			value = child
+			continue
+		}
+
 		value = getElemSafe(value)
 
 		switch value.Kind() {
 		case reflect.Map:
-			// Check that the map accept string keys
-			keyKind := value.Type().Key().Kind()
-			if keyKind != reflect.String && keyKind != reflect.Interface {
-				return value, "", ErrMapKeyNotString
+			key, found, err := d.findMapKey(value, fieldName)
+			if err != nil {
+				return value, err
 			}
 
 			// If a map key has to be set, skip the last attribute and return the map
 			if toSet && i == maxSetDepth {
-				return value, fieldName, nil
+				target.isChild = true
+				target.fieldName = fieldName
+				return value, nil
 			}
 
-			mapValue := value.MapIndex(reflect.ValueOf(fieldName))
-			if !mapValue.IsValid() {
-				return value, "", ErrNotFound
+			if !found {
+				return value, ErrNotFound
 			}
 
-			value = mapValue
+			parentMap, parentKey = value, key
+			value = value.MapIndex(key)
 
 		case reflect.Struct:
-			field, ok := value.Type().FieldByName(fieldName)
-			if !ok {
-				return value, "", ErrNotFound
-			}
-			// Check if field is unexported (method IsExported() was introduced in Go 1.17)
-			if field.PkgPath != "" {
-				return value, "", ErrUnexported
+			field, err := d.getStructField(value, fieldName, toSet)
+			if err != nil {
+				return value, err
 			}
 
-			value = value.FieldByName(fieldName)
+			value = field
 
 		case reflect.Slice, reflect.Array:
+			if d.opts.Syntax == JSONPointer {
+				sliceIndex, err := pointerIndex(fieldName)
+				if err != nil {
+					return value, err
+				}
+				if sliceIndex >= value.Len() {
+					return value, ErrIndexOutOfRange
+				}
+				value = value.Index(sliceIndex)
+				break
+			}
+
 			// Ignores field if it is the first one and it is empty. This
 			// happens when using brackets on a root slice (e.g. "[1].Name").
 			if i == 0 && fieldName == "" {
@@ -230,9 +460,9 @@ func getReflectValue(value reflect.Value, attribute string, sep string, toSet bo
 				fieldName = fieldName[1 : len(fieldName)-1]
 
 				// Try to apply the filter to the slice elements
-				foundValue, err := filterSlice(value, fieldName)
+				foundValue, err := d.filterSlice(value, fieldName)
 				if err != nil {
-					return value, "", err
+					return value, err
 				}
 				if foundValue.IsValid() {
 					value = foundValue
@@ -242,31 +472,47 @@ func getReflectValue(value reflect.Value, attribute string, sep string, toSet bo
 
 			sliceIndex, err := strconv.Atoi(fieldName)
 			if err != nil {
-				return value, "", ErrInvalidIndex
+				return value, ErrInvalidIndex
 			}
 			if sliceIndex < 0 || sliceIndex >= value.Len() {
-				return value, "", ErrIndexOutOfRange
+				return value, ErrIndexOutOfRange
 			}
 			field := value.Index(sliceIndex)
 			value = field
 
 		default:
-			return value, "", ErrNotFound
+			return value, ErrNotFound
 		}
 	}
 
-	return value, fieldName, nil
+	return value, nil
 }
 
-// getElemSafe returns the underlying value of an interface/pointer reflect.Value.
-func getElemSafe(v reflect.Value) reflect.Value {
-	switch v.Kind() {
-	case reflect.Ptr, reflect.Interface, reflect.Func, reflect.Chan, reflect.Map, reflect.Slice:
-		if v.IsNil() {
-			return v
+// settableCopy returns an addressable copy of the value held by v and true if
+// it cannot be modified in place (i.e. it is not addressable and it is not a
+// pointer).
+func settableCopy(v reflect.Value) (reflect.Value, bool) {
+	elem := v
+	if elem.Kind() == reflect.Interface {
+		if elem.IsNil() {
+			return v, false
 		}
+		elem = elem.Elem()
+	}
+	if elem.CanAddr() || elem.Kind() == reflect.Ptr {
+		return v, false
 	}
-	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+
+	c := reflect.New(elem.Type()).Elem()
+	c.Set(elem)
+	return c, true
+}
+
+// getElemSafe returns the underlying value of an interface/pointer reflect.Value,
+// following nested interfaces and pointers (e.g. an interface holding a
+// pointer).
+func getElemSafe(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
 		v = v.Elem()
 	}
 	return v
@@ -149,6 +149,16 @@ func TestDipper_Get(t *testing.T) {
 				},
 				attribute: "bar.1",
 			},
+			want: "a",
+		},
+		{
+			name: "map with unsupported key type",
+			args: args{
+				obj: map[float64]string{
+					1: "a",
+				},
+				attribute: "1",
+			},
 			want: dipper.ErrMapKeyNotString,
 		},
 		{
@@ -220,6 +230,16 @@ func TestDipper_Get(t *testing.T) {
 			},
 			want: 1,
 		},
+		{
+			name: "pointer in interface",
+			args: args{
+				obj: map[string]interface{}{
+					"book": getTestStruct(),
+				},
+				attribute: "book.Author.Name",
+			},
+			want: "Umberto Eco",
+		},
 		{
 			name: "slice using brackets notation from root",
 			args: args{
@@ -372,7 +392,8 @@ func TestDipper_GetMany(t *testing.T) {
 					"bar.2.0.bye",
 					"bar.2.0.extra.2",
 					"bar.3",         // out of range
-					"bar.2.1.1",     // not a map[string]
+					"bar.2.1.1",     // map[int]
+					"bar.2.1.x",     // invalid int key
 					"foo.bar.value", // does not exist
 					"foo.x",         // does not exist
 				},
@@ -384,7 +405,8 @@ func TestDipper_GetMany(t *testing.T) {
 				"bar.2.0.bye":     "adiós",
 				"bar.2.0.extra.2": 5.5,
 				"bar.3":           dipper.ErrIndexOutOfRange,
-				"bar.2.1.1":       dipper.ErrMapKeyNotString,
+				"bar.2.1.1":       "abc",
+				"bar.2.1.x":       dipper.ErrInvalidMapKey,
 				"foo.bar.value":   dipper.ErrNotFound,
 				"foo.x":           dipper.ErrNotFound,
 			},
@@ -627,6 +649,34 @@ func TestDipper_Set(t *testing.T) {
 				newValue: "First bug fix",
 			},
 		},
+		{
+			name: "update struct field of pointer in interface",
+			args: args{
+				attribute: "book.Title",
+				v: map[string]interface{}{
+					"book": getTestStruct(),
+				},
+				newValue: "Il pendolo di Foucault",
+			},
+			want: want{
+				result:   nil,
+				newValue: "Il pendolo di Foucault",
+			},
+		},
+		{
+			name: "replace map value",
+			args: args{
+				attribute: "Extra",
+				v: &Book{
+					Extra: map[string]interface{}{"foo": 1},
+				},
+				newValue: map[string]interface{}{"bar": 2},
+			},
+			want: want{
+				result:   nil,
+				newValue: map[string]interface{}{"bar": 2},
+			},
+		},
 		{
 			name: "delete map key",
 			args: args{
@@ -787,11 +837,58 @@ func TestDipper_Set(t *testing.T) {
 				newValue: "1980",
 			},
 		},
+		{
+			name: "set nil to interface",
+			args: args{
+				attribute: "Any",
+				v:         &Book{Any: "anything"},
+				newValue:  nil,
+			},
+			want: want{
+				result:   nil,
+				newValue: nil,
+			},
+		},
+		{
+			name: "set nil to map value",
+			args: args{
+				attribute: "foo",
+				v:         map[string]interface{}{"foo": "bar"},
+				newValue:  nil,
+			},
+			want: want{
+				result:   nil,
+				newValue: nil,
+			},
+		},
+		{
+			name: "set nil to int",
+			args: args{
+				attribute: "Year",
+				v:         &Book{Year: 1980},
+				newValue:  nil,
+			},
+			want: want{
+				result: dipper.ErrTypesDoNotMatch,
+			},
+		},
+		{
+			name: "set pointer to pointer field",
+			args: args{
+				attribute: "Publisher",
+				v:         &struct{ Publisher *Author }{},
+				newValue:  &Author{Name: "Chilton"},
+			},
+			want: want{
+				result:   nil,
+				newValue: &Author{Name: "Chilton"},
+			},
+		},
 		{
 			name: "update map value with invalid key type",
 			args: args{
 				attribute: "1",
-				v: map[int]interface{}{
+				v: map[float64]interface{}{
 					1: "Rendezvous with Rama",
 				},
 				newValue: "El nombre de la rosa",
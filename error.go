@@ -1,5 +1,11 @@
 package dipper
 
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
 // fieldError is an error indicating a wrong operation getting or setting a
 // value using the dipper package.
 type fieldError string
@@ -22,8 +28,13 @@ const (
 	// the size of the slice/array.
 	ErrIndexOutOfRange = fieldError("dipper: index out of range")
 	// ErrMapKeyNotString is the error returned when an attribute references a
-	// map whose keys are not of string type.
+	// map whose key type is not supported. Supported key types are strings,
+	// integers, booleans, interfaces and types implementing
+	// encoding.TextUnmarshaler.
 	ErrMapKeyNotString = fieldError("dipper: map key is not of string type")
+	// ErrInvalidMapKey is the error returned when an attribute references a
+	// map element, but the given key cannot be converted to the map key type.
+	ErrInvalidMapKey = fieldError("dipper: invalid map key")
 	// ErrUnexported is the error returned when an attribute references an
 	// unexported struct field.
 	ErrUnexported = fieldError("dipper: field is unexported")
@@ -39,20 +50,102 @@ const (
 	// ErrFilterNotFound is the error returned when a search expression does not
 	// match any entries.
 	ErrFilterNotFound = fieldError("dipper: no matches for filter expression")
+	// ErrAmbiguousField is the error returned when an attribute is accessed
+	// without regard to case and it matches several struct fields or map keys.
+	ErrAmbiguousField = fieldError("dipper: ambiguous field name")
 	// ErrInvalidFilterValue is the error returned when a search expression has an
 	// invalid value.
 	ErrInvalidFilterValue = fieldError("dipper: invalid value for filter expression")
+	// ErrInvalidJSON is the error returned when a JSON document to be modified
+	// is not valid.
+	ErrInvalidJSON = fieldError("dipper: invalid JSON document")
+	// ErrInvalidPointer is the error returned when an attribute is not a valid
+	// JSON Pointer using the JSONPointer syntax.
+	ErrInvalidPointer = fieldError("dipper: invalid JSON pointer")
+	// ErrInvalidPatch is the error returned when a JSON Patch operation is
+	// unknown or invalid (e.g. moving a value into one of its children).
+	ErrInvalidPatch = fieldError("dipper: invalid patch operation")
+	// ErrTestFailed is the error returned when a JSON Patch "test" operation
+	// fails.
+	ErrTestFailed = fieldError("dipper: patch test failed")
+	// ErrInvalidMethod is the error returned when an attribute calls a method
+	// that takes arguments or does not return a value (and optionally an
+	// error).
+	ErrInvalidMethod = fieldError("dipper: invalid method signature")
+	// ErrMethodNotSettable is the error returned from a set operation when an
+	// attribute calls a method.
+	ErrMethodNotSettable = fieldError("dipper: method result cannot be set")
+	// ErrInvalidSpec is the error returned when a Transform() spec has a value
+	// that is not an attribute, a TransformField or a nested spec.
+	ErrInvalidSpec = fieldError("dipper: invalid transform spec")
 )
 
-// IsFieldError returns true when the given value is a fieldError.
+// PathError records an error that occurred while accessing an attribute, and
+// the path of the attribute field that caused it (e.g. the error returned by
+// a method called in the attribute).
+type PathError struct {
+	Path string
+	Err  error
+}
+
+// Error returns the message of the underlying error, prefixed with the path.
+// The "dipper: " prefix of the package errors is not repeated.
+func (e *PathError) Error() string {
+	return "dipper: " + e.Path + ": " + strings.TrimPrefix(e.Err.Error(), "dipper: ")
+}
+
+// Unwrap returns the underlying error.
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// TypeMismatchError records a value whose type is not the expected one (e.g.
+// the error returned by GetAs()). It matches ErrTypesDoNotMatch using
+// errors.Is().
+type TypeMismatchError struct {
+	Path     string
+	Expected reflect.Type
+	// Actual is the type of the value, or nil if the value is nil
+	Actual reflect.Type
+}
+
+func (e *TypeMismatchError) Error() string {
+	actual := "nil"
+	if e.Actual != nil {
+		actual = e.Actual.String()
+	}
+	return "dipper: " + e.Path + ": expected " + e.Expected.String() + ", got " + actual
+}
+
+// Is returns true if the target is ErrTypesDoNotMatch.
+func (e *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypesDoNotMatch
+}
+
+// isNotFound returns true if the given error means that an attribute does not
+// reference any value (ErrNotFound, ErrIndexOutOfRange or ErrFilterNotFound),
+// as opposed to an invalid attribute.
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, ErrIndexOutOfRange) || errors.Is(err, ErrFilterNotFound)
+}
+
+// IsFieldError returns true when the given value is a fieldError or a
+// *PathError.
 func IsFieldError(v interface{}) bool {
-	_, ok := v.(fieldError)
-	return ok
+	switch v.(type) {
+	case fieldError, *PathError:
+		return true
+	}
+	return false
 }
 
-// Error casts the given value to fieldError if possible, otherwise returns nil.
+// Error casts the given value to fieldError or *PathError if possible,
+// otherwise returns nil.
 func Error(v interface{}) error {
-	if err, ok := v.(fieldError); ok {
+	switch err := v.(type) {
+	case fieldError:
+		return err
+	case *PathError:
 		return err
 	}
 	return nil
@@ -66,8 +159,8 @@ func (f Fields) HasErrors() bool {
 // FirstError returns the first fieldError found in this Fields map.
 func (f Fields) FirstError() error {
 	for _, v := range f {
-		if IsFieldError(v) {
-			return v.(fieldError)
+		if err := Error(v); err != nil {
+			return err
 		}
 	}
 	return nil
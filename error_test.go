@@ -1,6 +1,7 @@
 package dipper_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -185,3 +186,28 @@ func TestFields_FirstError(t *testing.T) {
 		})
 	}
 }
+
+func TestPathError(t *testing.T) {
+	cause := errors.New("some error")
+	err := &dipper.PathError{Path: "Books.0.Total()", Err: cause}
+
+	if got, want := err.Error(), "dipper: Books.0.Total(): some error"; got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+	wrapped := &dipper.PathError{Path: "Books.[*].Title", Err: dipper.ErrInvalidIndex}
+	if got, want := wrapped.Error(), "dipper: Books.[*].Title: invalid index"; got != want {
+		t.Errorf("Error() = %v, want %v", got, want)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("errors.Is() = false, want true")
+	}
+	if !dipper.IsFieldError(err) {
+		t.Errorf("IsFieldError() = false, want true")
+	}
+	if got := dipper.Error(err); got != err {
+		t.Errorf("Error() = %v, want %v", got, err)
+	}
+	if got := (dipper.Fields{"Books.0.Total()": err}).FirstError(); got != err {
+		t.Errorf("FirstError() = %v, want %v", got, err)
+	}
+}
@@ -0,0 +1,294 @@
+package dipper
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+	"unsafe"
+)
+
+// structField holds the information of a struct field that can be accessed
+// with an attribute.
+type structField struct {
+	name     string
+	index    []int
+	exported bool
+	// inline is true for embedded structs whose fields are promoted
+	inline bool
+}
+
+// structFields holds the accessible fields of a struct type, indexed by the
+// name used to access them.
+type structFields struct {
+	list   []structField
+	byName map[string]int
+	// byFoldName indexes the fields by foldName(name)
+	byFoldName map[string][]int
+}
+
+// structFieldsKey is the key type of structFieldsCache.
+type structFieldsKey struct {
+	t       reflect.Type
+	tagName string
+}
+
+// structFieldsCache stores the structFields of every struct type and tag name
+// resolved so far.
+var structFieldsCache sync.Map // map[structFieldsKey]*structFields
+
+// cachedStructFields returns the structFields of the given struct type, using
+// the tag name to get the field names. If tagName is empty, the Go field names
+// are used.
+func cachedStructFields(t reflect.Type, tagName string) *structFields {
+	key := structFieldsKey{t: t, tagName: tagName}
+	if f, ok := structFieldsCache.Load(key); ok {
+		return f.(*structFields)
+	}
+	f, _ := structFieldsCache.LoadOrStore(key, typeStructFields(t, tagName))
+	return f.(*structFields)
+}
+
+// typeStructFields returns the structFields of the given struct type.
+// Fields of embedded structs are promoted following the Go rules: a field at
+// a shallower depth hides the deeper ones with the same name, and fields with
+// the same name at the same depth are ambiguous and not accessible.
+// If tagName is not empty, the name given in the tag is used as the field name
+// (falling back to the Go name if it is empty), and fields tagged as "-" are
+// ignored.
+func typeStructFields(t reflect.Type, tagName string) *structFields {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	fields := &structFields{
+		byName:     map[string]int{},
+		byFoldName: map[string][]int{},
+	}
+	hidden := map[string]bool{}
+	visited := map[reflect.Type]bool{}
+
+	next := []embedded{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+
+		var level []structField
+		count := map[string]int{}
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+
+				name := sf.Name
+				tagged := false
+				if tagName != "" {
+					tag := sf.Tag.Get(tagName)
+					if tag == "-" {
+						continue
+					}
+					if n := strings.Split(tag, ",")[0]; n != "" {
+						name = n
+						tagged = true
+					}
+				}
+
+				index := make([]int, len(e.index)+1)
+				copy(index, e.index)
+				index[len(e.index)] = i
+
+				field := structField{
+					name:     name,
+					index:    index,
+					exported: sf.PkgPath == "",
+				}
+
+				if sf.Anonymous && !tagged {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, embedded{typ: ft, index: index})
+						field.inline = true
+					}
+				}
+
+				level = append(level, field)
+				count[name]++
+			}
+		}
+
+		for _, f := range level {
+			if hidden[f.name] || count[f.name] > 1 {
+				continue
+			}
+			fold := foldName(f.name)
+			fields.byName[f.name] = len(fields.list)
+			fields.byFoldName[fold] = append(fields.byFoldName[fold], len(fields.list))
+			fields.list = append(fields.list, f)
+		}
+		for name := range count {
+			hidden[name] = true
+		}
+	}
+
+	return fields
+}
+
+// foldName returns the canonical form of the given name under Unicode case
+// folding, which is the same for all the names that strings.EqualFold()
+// considers equal.
+func foldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		n := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < n {
+				n = f
+			}
+		}
+		return n
+	}, name)
+}
+
+// lookupStructField returns the index in fields.list of the field accessed by
+// the given name, according to the Dipper options.
+// Without regard to case, unexported fields are not taken into account when
+// they cannot be accessed, so they do not make an exported field ambiguous.
+func (d *Dipper) lookupStructField(fields *structFields, name string) (int, error) {
+	if i, ok := fields.byName[name]; ok {
+		return i, nil
+	}
+	if !d.opts.CaseInsensitive {
+		return 0, ErrNotFound
+	}
+
+	matches := fields.byFoldName[foldName(name)]
+	if !d.opts.AllowUnexported {
+		var accessible []int
+		for _, i := range matches {
+			if fields.list[i].exported {
+				accessible = append(accessible, i)
+			}
+		}
+		if len(accessible) > 0 {
+			matches = accessible
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, ErrNotFound
+	case 1:
+		return matches[0], nil
+	}
+	return 0, ErrAmbiguousField
+}
+
+// fieldByIndex returns the nested field of v corresponding to index, like
+// reflect.Value.FieldByIndex. If a nil embedded pointer is found, a new value
+// is allocated when alloc is true and the pointer is settable, otherwise an
+// invalid reflect.Value is returned.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// getStructField returns the field of the given struct value accessed by the
+// given name, according to the Dipper options.
+// toSet indicates that nil embedded pointers found on the way to the field can
+// be initialized.
+func (d *Dipper) getStructField(value reflect.Value, name string, toSet bool) (reflect.Value, error) {
+	fields := cachedStructFields(value.Type(), d.opts.TagName)
+
+	i, err := d.lookupStructField(fields, name)
+	if err != nil {
+		return value, err
+	}
+
+	field := fields.list[i]
+	if field.exported {
+		fieldValue := fieldByIndex(value, field.index, toSet)
+		if !fieldValue.IsValid() {
+			return value, ErrNotFound
+		}
+		return fieldValue, nil
+	}
+
+	if !d.opts.AllowUnexported || (toSet && !d.opts.AllowUnexportedSet) {
+		return value, ErrUnexported
+	}
+
+	// Unexported fields can only be accessed through their address, so an
+	// addressable copy of the struct is used if it is not addressable.
+	if !value.CanAddr() {
+		if toSet {
+			return value, ErrUnaddressable
+		}
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+
+	fieldValue := fieldByIndex(value, field.index, toSet)
+	if !fieldValue.IsValid() {
+		return value, ErrNotFound
+	}
+	return reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr())).Elem(), nil
+}
+
+// namedValue is a value and the name used to access it.
+type namedValue struct {
+	name  string
+	value reflect.Value
+}
+
+// structFieldValues returns the fields of the given struct value that can be
+// accessed according to the Dipper options, with the fields of embedded
+// structs after the fields of the outer struct. Embedded structs whose fields
+// are promoted are not included, but their fields are.
+func (d *Dipper) structFieldValues(value reflect.Value) []namedValue {
+	fields := cachedStructFields(value.Type(), d.opts.TagName)
+
+	var values []namedValue
+	for _, f := range fields.list {
+		if f.inline {
+			continue
+		}
+		fieldValue, err := d.getStructField(value, f.name, false)
+		if err != nil {
+			continue
+		}
+		values = append(values, namedValue{name: f.name, value: fieldValue})
+	}
+	return values
+}
+
+// hasExportedFields returns true if the given struct type has any accessible
+// exported field. Structs without them (e.g. time.Time) are handled as opaque
+// values.
+func hasExportedFields(t reflect.Type) bool {
+	for _, f := range cachedStructFields(t, "").list {
+		if f.exported {
+			return true
+		}
+	}
+	return false
+}
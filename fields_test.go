@@ -0,0 +1,442 @@
+package dipper_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/flusflas/dipper"
+)
+
+type Base struct {
+	ID      int    `json:"id"`
+	Comment string `json:"comment"`
+}
+
+type Extension struct {
+	Comment string `json:"comment"`
+	Hidden  string `json:"hidden"`
+}
+
+type TaggedItem struct {
+	Base
+	*Extension
+	Name     string `json:"name,omitempty"`
+	Untagged string
+	Options  string `json:",omitempty"`
+	Ignored  string `json:"-"`
+	Renamed  string `json:"Name"`
+	internal string
+}
+
+func TestDipper_GetWithTagName(t *testing.T) {
+	item := TaggedItem{
+		Base:      Base{ID: 7, Comment: "base comment"},
+		Extension: &Extension{Comment: "extension comment", Hidden: "extension hidden"},
+		Name:      "foo",
+		Untagged:  "bar",
+		Options:   "baz",
+		Ignored:   "ignored",
+		Renamed:   "renamed",
+		internal:  "internal",
+	}
+
+	tests := []struct {
+		name      string
+		tagName   string
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "tagged field",
+			tagName:   "json",
+			obj:       getTestStruct(),
+			attribute: "author.name",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "go name is not used for tagged fields",
+			tagName:   "json",
+			obj:       getTestStruct(),
+			attribute: "Author",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "tagged field in slice with filter",
+			tagName:   "json",
+			obj:       getTestStruct(),
+			attribute: "genres[name='Crime'].id",
+			want:      1,
+		},
+		{
+			name:      "tag with options",
+			tagName:   "json",
+			obj:       item,
+			attribute: "name",
+			want:      "foo",
+		},
+		{
+			name:      "untagged field falls back to go name",
+			tagName:   "json",
+			obj:       item,
+			attribute: "Untagged",
+			want:      "bar",
+		},
+		{
+			name:      "tag with empty name falls back to go name",
+			tagName:   "json",
+			obj:       item,
+			attribute: "Options",
+			want:      "baz",
+		},
+		{
+			name:      "ignored field",
+			tagName:   "json",
+			obj:       item,
+			attribute: "Ignored",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "tag name matching another go name",
+			tagName:   "json",
+			obj:       item,
+			attribute: "Name",
+			want:      "renamed",
+		},
+		{
+			name:      "promoted field",
+			tagName:   "json",
+			obj:       item,
+			attribute: "id",
+			want:      7,
+		},
+		{
+			name:      "promoted field from embedded pointer",
+			tagName:   "json",
+			obj:       item,
+			attribute: "hidden",
+			want:      "extension hidden",
+		},
+		{
+			name:      "ambiguous promoted field",
+			tagName:   "json",
+			obj:       item,
+			attribute: "comment",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "embedded struct by go name",
+			tagName:   "json",
+			obj:       item,
+			attribute: "Base.comment",
+			want:      "base comment",
+		},
+		{
+			name:      "nil embedded pointer",
+			tagName:   "json",
+			obj:       TaggedItem{},
+			attribute: "hidden",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "unexported field",
+			tagName:   "json",
+			obj:       item,
+			attribute: "internal",
+			want:      dipper.ErrUnexported,
+		},
+		{
+			name:      "promoted field without tag name",
+			obj:       item,
+			attribute: "Hidden",
+			want:      "extension hidden",
+		},
+		{
+			name:      "tags are ignored without tag name",
+			obj:       item,
+			attribute: "name",
+			want:      dipper.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{TagName: tt.tagName})
+			got := d.Get(tt.obj, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_SetWithTagName(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       interface{}
+		attribute string
+		newValue  interface{}
+		want      error
+	}{
+		{
+			name:      "tagged field",
+			obj:       getTestStruct(),
+			attribute: "author.name",
+			newValue:  "Jorge Luis Borges",
+		},
+		{
+			name:      "tagged field in slice with filter",
+			obj:       getTestStruct(),
+			attribute: "genres[id=1].name",
+			newValue:  "Thriller",
+		},
+		{
+			name:      "promoted field in nil embedded pointer",
+			obj:       &TaggedItem{},
+			attribute: "hidden",
+			newValue:  "new hidden",
+		},
+		{
+			name:      "ignored field",
+			obj:       &TaggedItem{},
+			attribute: "Ignored",
+			newValue:  "new ignored",
+			want:      dipper.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{TagName: "json"})
+			err := d.Set(tt.obj, tt.attribute, tt.newValue)
+			if !reflect.DeepEqual(err, tt.want) {
+				t.Fatalf("Set() = %v, want %v", err, tt.want)
+			}
+			if err == nil {
+				if got := d.Get(tt.obj, tt.attribute); !reflect.DeepEqual(got, tt.newValue) {
+					t.Errorf("Set() => Value did not change to %v", tt.newValue)
+				}
+			}
+		})
+	}
+}
+
+func TestDipper_GetCaseInsensitive(t *testing.T) {
+	type Collision struct {
+		Name string
+		NAME string
+	}
+	type Hidden struct {
+		Name string
+		name string
+	}
+	type Folded struct {
+		Status string `json:"ſtatus"`
+	}
+
+	tests := []struct {
+		name      string
+		tagName   string
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "lowercase path",
+			obj:       getTestStruct(),
+			attribute: "author.name",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "uppercase path",
+			obj:       getTestStruct(),
+			attribute: "AUTHOR.NAME",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "tag names",
+			tagName:   "json",
+			obj:       getTestStruct(),
+			attribute: "AUTHOR.BIRTH_DATE",
+			want:      mustParseDate("1932-07-05"),
+		},
+		{
+			name:      "filter key",
+			obj:       getTestStruct(),
+			attribute: "genres[name='Crime'].ID",
+			want:      1,
+		},
+		{
+			name:      "exact match takes precedence",
+			obj:       Collision{Name: "foo", NAME: "bar"},
+			attribute: "NAME",
+			want:      "bar",
+		},
+		{
+			name:      "ambiguous field",
+			obj:       Collision{Name: "foo", NAME: "bar"},
+			attribute: "name",
+			want:      dipper.ErrAmbiguousField,
+		},
+		{
+			name:      "inaccessible unexported field is not ambiguous",
+			obj:       Hidden{Name: "foo", name: "bar"},
+			attribute: "NAME",
+			want:      "foo",
+		},
+		{
+			name:      "unicode case folding",
+			tagName:   "json",
+			obj:       Folded{Status: "ok"},
+			attribute: "STATUS",
+			want:      "ok",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{TagName: tt.tagName, CaseInsensitive: true})
+			got := d.Get(tt.obj, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_AllowUnexported(t *testing.T) {
+	type counter struct {
+		count int
+	}
+	type Wrapper struct {
+		Name    string
+		counter counter
+		tags    []string
+		ptr     *counter
+	}
+
+	newWrapper := func() *Wrapper {
+		return &Wrapper{
+			Name:    "foo",
+			counter: counter{count: 3},
+			tags:    []string{"a", "b"},
+			ptr:     &counter{count: 5},
+		}
+	}
+
+	t.Run("get", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			opts      dipper.Options
+			obj       interface{}
+			attribute string
+			want      interface{}
+		}{
+			{
+				name:      "disabled by default",
+				obj:       newWrapper(),
+				attribute: "counter.count",
+				want:      dipper.ErrUnexported,
+			},
+			{
+				name:      "nested unexported field",
+				opts:      dipper.Options{AllowUnexported: true},
+				obj:       newWrapper(),
+				attribute: "counter.count",
+				want:      3,
+			},
+			{
+				name:      "unexported field in unaddressable struct",
+				opts:      dipper.Options{AllowUnexported: true},
+				obj:       *newWrapper(),
+				attribute: "tags.1",
+				want:      "b",
+			},
+			{
+				name:      "unexported pointer field",
+				opts:      dipper.Options{AllowUnexported: true},
+				obj:       newWrapper(),
+				attribute: "ptr.count",
+				want:      5,
+			},
+			{
+				name:      "unexported field of standard library type",
+				opts:      dipper.Options{AllowUnexported: true},
+				obj:       getTestStruct(),
+				attribute: "Author.BirthDate.loc",
+				want:      (*time.Location)(nil),
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				d := dipper.New(tt.opts)
+				got := d.Get(tt.obj, tt.attribute)
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Get() = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			opts      dipper.Options
+			obj       interface{}
+			attribute string
+			newValue  interface{}
+			want      error
+		}{
+			{
+				name:      "set requires AllowUnexportedSet",
+				opts:      dipper.Options{AllowUnexported: true},
+				obj:       newWrapper(),
+				attribute: "counter.count",
+				newValue:  10,
+				want:      dipper.ErrUnexported,
+			},
+			{
+				name:      "set requires AllowUnexported",
+				opts:      dipper.Options{AllowUnexportedSet: true},
+				obj:       newWrapper(),
+				attribute: "counter.count",
+				newValue:  10,
+				want:      dipper.ErrUnexported,
+			},
+			{
+				name:      "nested unexported field",
+				opts:      dipper.Options{AllowUnexported: true, AllowUnexportedSet: true},
+				obj:       newWrapper(),
+				attribute: "counter.count",
+				newValue:  10,
+			},
+			{
+				name:      "unexported slice element",
+				opts:      dipper.Options{AllowUnexported: true, AllowUnexportedSet: true},
+				obj:       newWrapper(),
+				attribute: "tags.0",
+				newValue:  "z",
+			},
+			{
+				name:      "unaddressable struct",
+				opts:      dipper.Options{AllowUnexported: true, AllowUnexportedSet: true},
+				obj:       *newWrapper(),
+				attribute: "counter.count",
+				newValue:  10,
+				want:      dipper.ErrUnaddressable,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				d := dipper.New(tt.opts)
+				err := d.Set(tt.obj, tt.attribute, tt.newValue)
+				if !reflect.DeepEqual(err, tt.want) {
+					t.Fatalf("Set() = %v, want %v", err, tt.want)
+				}
+				if err == nil {
+					if got := d.Get(tt.obj, tt.attribute); !reflect.DeepEqual(got, tt.newValue) {
+						t.Errorf("Set() => Value did not change to %v", tt.newValue)
+					}
+				}
+			})
+		}
+	})
+}
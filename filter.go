@@ -1,6 +1,7 @@
 package dipper
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -13,15 +14,26 @@ var filterRegex = regexp.MustCompile(`(?m)^([\w-]*)==?(.*)$`)
 // filterSlice takes a slice value and applies on it the given filter
 // expression. It returns the first value matching the filter or an empty
 // reflect.Value if no match was found.
-func filterSlice(value reflect.Value, fieldName string) (reflect.Value, error) {
+func (d *Dipper) filterSlice(value reflect.Value, fieldName string) (reflect.Value, error) {
+	i, err := d.filterIndex(value, fieldName)
+	if i < 0 {
+		return reflect.Value{}, err
+	}
+	return value.Index(i), nil
+}
+
+// filterIndex takes a slice value and applies on it the given filter
+// expression. It returns the index of the first value matching the filter, or
+// -1 if the field is not a filter expression or no match was found.
+func (d *Dipper) filterIndex(value reflect.Value, fieldName string) (int, error) {
 	if !strings.Contains(fieldName, "=") {
-		return reflect.Value{}, nil
+		return -1, nil
 	}
 
 	// Parse filter expression
 	match := filterRegex.FindStringSubmatch(fieldName)
 	if match == nil {
-		return reflect.Value{}, ErrInvalidFilterExpression
+		return -1, ErrInvalidFilterExpression
 	}
 
 	// This function converts the filter value string to the proper type
@@ -49,12 +61,15 @@ func filterSlice(value reflect.Value, fieldName string) (reflect.Value, error) {
 	filterKey := match[1]
 	filterValue, err := parseFilterValue(match[2])
 	if err != nil {
-		return reflect.Value{}, err
+		return -1, err
 	}
 
 	// This function returns the numeric value of the given reflect.Value in
 	// float64 or an error if the value is not numerical.
 	toFloat64 := func(v reflect.Value) (float64, error) {
+		if v.IsValid() && v.Type() == jsonNumberType {
+			return json.Number(v.String()).Float64()
+		}
 		switch v.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			return float64(v.Int()), nil
@@ -79,39 +94,36 @@ func filterSlice(value reflect.Value, fieldName string) (reflect.Value, error) {
 		return reflect.DeepEqual(v.Interface(), filterValue)
 	}
 
-	// Iterates over the value elements and returns the first matching value
+	// Iterates over the value elements and returns the first matching index
 	for i := 0; i < value.Len(); i++ {
 		item := value.Index(i)
 		itemSafe := getElemSafe(item)
 
 		switch itemSafe.Kind() {
 		case reflect.Map:
-			for _, mapKey := range itemSafe.MapKeys() {
-				if mapKey.String() != filterKey {
-					continue
-				}
-
-				if compareValues(itemSafe.MapIndex(mapKey)) {
-					return item, nil
-				}
+			mapKey, found, err := d.findMapKey(itemSafe, filterKey)
+			if err != nil || !found {
+				continue
+			}
+
+			if compareValues(itemSafe.MapIndex(mapKey)) {
+				return i, nil
 			}
 		case reflect.Struct:
-			for i := 0; i < itemSafe.NumField(); i++ {
-				if itemSafe.Type().Field(i).Name != filterKey {
-					continue
-				}
-
-				field := itemSafe.Field(i)
-				if compareValues(field) {
-					return item, nil
-				}
+			field, err := d.getStructField(itemSafe, filterKey, false)
+			if err != nil {
+				continue
+			}
+
+			if compareValues(field) {
+				return i, nil
 			}
 		default:
 			if filterKey == "" && compareValues(item) {
-				return item, nil
+				return i, nil
 			}
 		}
 	}
 
-	return reflect.Value{}, ErrFilterNotFound
+	return -1, ErrFilterNotFound
 }
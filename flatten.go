@@ -0,0 +1,303 @@
+package dipper
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flatten returns a map with the leaf values of obj, using their attributes as
+// keys (e.g. "Books.0.Genres.1"). Structs, maps, slices and arrays are
+// traversed, as well as custom containers, sync.Map, atomic.Value and raw JSON
+// values. Empty containers and nil values are leaves, so no information is
+// lost. Struct fields are named as they are accessed in attributes (e.g.
+// according to the TagName option), and structs without exported fields (e.g.
+// time.Time) are leaves.
+// Pointer cycles are not followed: a pointer to a value that is already being
+// flattened is ignored.
+//
+// Example:
+//
+//	 // Using "." as the Dipper separator
+//		for key, value := range my_dipper.Flatten(config) {
+//		    fmt.Printf("%s=%v\n", key, value)
+//		}
+func (d *Dipper) Flatten(obj interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	w := walker{d: d, visiting: map[uintptr]bool{}}
+	w.fn = func(path string, v reflect.Value, children []namedValue, _ int) WalkAction {
+		if len(children) > 0 {
+			return Continue
+		}
+		if v.IsValid() && v.CanInterface() {
+			result[path] = v.Interface()
+		} else {
+			result[path] = nil
+		}
+		return Continue
+	}
+	w.walk(reflect.ValueOf(obj), "", 0)
+
+	return result
+}
+
+// Unflatten sets the values of the given map, whose keys are attributes (e.g.
+// the map returned by Dipper.Flatten()), into target, which must be
+// addressable. Every attribute is resolved against the actual values of
+// target, as Dipper.Set() does, creating the missing values on the way: nil
+// pointers and maps are initialized, slices are extended up to the given
+// indexes, and nil interface{} values are filled with map[string]interface{}
+// values.
+// Values are converted to the types of the target fields if needed, and string
+// values are also parsed as JSON (e.g. "8080" to an int), so flat
+// configurations (e.g. environment variables) can be loaded into structs.
+// It returns ErrTypesDoNotMatch if an attribute is both a leaf and the parent
+// of another attribute (e.g. "a" and "a.b"). Otherwise, target can be
+// partially modified if an error occurs.
+//
+// Example:
+//
+//	var config Config
+//	err := my_dipper.Unflatten(map[string]interface{}{"Server.Port": "8080"}, &config)
+//	if err != nil {
+//	    return err
+//	}
+func (d *Dipper) Unflatten(m map[string]interface{}, target interface{}) error {
+	attributes := make([]string, 0, len(m))
+	for attribute := range m {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	splitters := make([]fieldSplitter, len(attributes))
+	for i, attribute := range attributes {
+		splitter, err := d.newSplitter(attribute)
+		if err != nil {
+			return err
+		}
+		if err := d.checkFlatParents(m, attribute); err != nil {
+			return err
+		}
+		splitters[i] = splitter
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ErrUnaddressable
+		}
+		value = value.Elem()
+	}
+	if !value.CanSet() && (value.Kind() != reflect.Map || value.IsNil()) {
+		return ErrUnaddressable
+	}
+
+	u := unflattener{d: d, mg: merger{d: d, parseStrings: true}}
+	for i, attribute := range attributes {
+		if err := u.set(value, splitters[i], reflect.ValueOf(m[attribute]), attribute); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFlatParents returns ErrTypesDoNotMatch if the parent of the given
+// attribute, or any of its ancestors, is also a key of the flat map m.
+func (d *Dipper) checkFlatParents(m map[string]interface{}, attribute string) error {
+	if attribute == "" {
+		return nil
+	}
+
+	splitter, err := d.newSplitter(attribute)
+	if err != nil {
+		return err
+	}
+	for splitter.HasMore() {
+		if _, ok := m[splitter.Parsed()]; ok {
+			return ErrTypesDoNotMatch
+		}
+		splitter.Next()
+	}
+	return nil
+}
+
+// unflattener sets the values of a flat map into a target value.
+type unflattener struct {
+	d  *Dipper
+	mg merger
+}
+
+// set sets src into the value referenced by the remaining fields of the
+// splitter, starting from the given value. The values found on the way are
+// created if they are missing, and the values held by maps and interfaces are
+// modified in a copy that is set back.
+func (u *unflattener) set(value reflect.Value, splitter fieldSplitter, src reflect.Value, attribute string) error {
+	if !splitter.HasMore() || attribute == "" {
+		return u.mg.merge(value, src, attribute)
+	}
+
+	// Raw JSON documents, atomic values and custom containers are set as
+	// Dipper.Set() does
+	if u.isOpaque(value) {
+		if !value.CanAddr() {
+			return ErrUnaddressable
+		}
+		var new interface{}
+		if src.IsValid() {
+			new = src.Interface()
+		}
+		return u.d.setCoerced(value.Addr().Interface(), splitter.Remaining(), new)
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			if !value.CanSet() {
+				return ErrUnaddressable
+			}
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return u.set(value.Elem(), splitter, src, attribute)
+
+	case reflect.Interface:
+		if !value.CanSet() {
+			return ErrUnaddressable
+		}
+		if value.IsNil() {
+			value.Set(reflect.ValueOf(map[string]interface{}{}))
+		}
+		elem := value.Elem()
+		if elem.Kind() == reflect.Ptr {
+			return u.set(elem, splitter, src, attribute)
+		}
+		c := reflect.New(elem.Type()).Elem()
+		c.Set(elem)
+		if err := u.set(c, splitter, src, attribute); err != nil {
+			return err
+		}
+		value.Set(c)
+		return nil
+	}
+
+	fieldName, i := splitter.Next()
+
+	switch value.Kind() {
+	case reflect.Map:
+		if value.IsNil() {
+			if !value.CanSet() {
+				return ErrUnaddressable
+			}
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+		key, found, err := u.d.findMapKey(value, fieldName)
+		if err != nil {
+			return err
+		}
+
+		// Map values are not settable, so they are modified in a copy
+		elem := reflect.New(value.Type().Elem()).Elem()
+		if found {
+			elem.Set(value.MapIndex(key))
+		}
+		if err := u.set(elem, splitter, src, attribute); err != nil {
+			return err
+		}
+		value.SetMapIndex(key, elem)
+		return nil
+
+	case reflect.Struct:
+		field, err := u.d.getStructField(value, fieldName, true)
+		if err != nil {
+			return err
+		}
+		return u.set(field, splitter, src, attribute)
+
+	case reflect.Slice, reflect.Array:
+		// Ignores field if it is the first one and it is empty, as in
+		// Dipper.Get() (e.g. "[1].Name")
+		if i == 0 && fieldName == "" && u.d.opts.Syntax != JSONPointer {
+			return u.set(value, splitter, src, attribute)
+		}
+
+		index, err := u.index(value, fieldName)
+		if err != nil {
+			return err
+		}
+		if n := index + 1 - value.Len(); n > 0 {
+			if value.Kind() == reflect.Array {
+				return ErrIndexOutOfRange
+			}
+			if !value.CanSet() {
+				return ErrUnaddressable
+			}
+			value.Set(reflect.AppendSlice(value, reflect.MakeSlice(value.Type(), n, n)))
+		}
+		return u.set(value.Index(index), splitter, src, attribute)
+	}
+
+	return ErrNotFound
+}
+
+// isOpaque returns true if the given value is a raw JSON document, an
+// atomic.Value or a value handled by a Resolver, whose children are set by
+// Dipper.Set().
+func (u *unflattener) isOpaque(value reflect.Value) bool {
+	if u.d.getRawJSON(value).IsValid() {
+		return true
+	}
+	if r, _ := u.d.getResolver(value); r != nil {
+		return true
+	}
+	_, ok, _ := atomicValue(value)
+	return ok
+}
+
+// index returns the index of the element of the given slice or array accessed
+// by the field name, which can be an index (in brackets or not), a filter
+// expression, or "-" (the position after the last element) using the
+// JSONPointer syntax.
+func (u *unflattener) index(value reflect.Value, fieldName string) (int, error) {
+	if u.d.opts.Syntax == JSONPointer {
+		if fieldName == "-" {
+			return value.Len(), nil
+		}
+		return pointerIndex(fieldName)
+	}
+
+	if strings.HasPrefix(fieldName, "[") && strings.HasSuffix(fieldName, "]") {
+		fieldName = fieldName[1 : len(fieldName)-1]
+		index, err := u.d.filterIndex(value, fieldName)
+		if err != nil || index >= 0 {
+			return index, err
+		}
+	}
+
+	index, err := strconv.Atoi(fieldName)
+	if err != nil {
+		return -1, ErrInvalidIndex
+	}
+	if index < 0 {
+		return -1, ErrIndexOutOfRange
+	}
+	return index, nil
+}
+
+// splitFields returns the fields of the given attribute.
+func (d *Dipper) splitFields(attribute string) ([]string, error) {
+	if attribute == "" {
+		return nil, nil
+	}
+
+	splitter, err := d.newSplitter(attribute)
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	for splitter.HasMore() {
+		field, _ := splitter.Next()
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
@@ -0,0 +1,289 @@
+package dipper_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+type Inventory struct {
+	Slots  [3]int
+	ByID   map[int]string
+	Counts map[string]int
+	Items  map[string]Route
+	Tags   []string
+	Owner  *Author
+}
+
+func TestDipper_Flatten(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	cycle := &node{Name: "a"}
+	cycle.Next = cycle
+
+	tests := []struct {
+		name string
+		opts dipper.Options
+		obj  interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "struct",
+			obj:  getTestStruct(),
+			want: map[string]interface{}{
+				"Title":                "El nombre de la rosa",
+				"Year":                 1980,
+				"Author.Name":          "Umberto Eco",
+				"Author.BirthDate":     mustParseDate("1932-07-05"),
+				"GenreNames.0":         "Mystery",
+				"GenreNames.1":         "Crime",
+				"Genres.0.ID":          0,
+				"Genres.0.Name":        "Mystery",
+				"Genres.0.Description": getTestStruct().Genres[0].Description,
+				"Genres.1.ID":          1,
+				"Genres.1.Name":        "Crime",
+				"Genres.1.Description": getTestStruct().Genres[1].Description,
+				"Extra.foo.bar":        123,
+				"Any":                  nil,
+				"ISBN":                 "1234567890",
+			},
+		},
+		{
+			name: "tag names and JSON pointer syntax",
+			opts: dipper.Options{TagName: "json", Syntax: dipper.JSONPointer},
+			obj:  &TLSConfig{Cert: "cert.pem"},
+			want: map[string]interface{}{"/cert": "cert.pem", "/key": ""},
+		},
+		{
+			name: "empty containers",
+			obj:  map[string]interface{}{"a": []int{}, "b": map[string]int{}, "c": []byte("xyz")},
+			want: map[string]interface{}{"a": []int{}, "b": map[string]int{}, "c": []byte("xyz")},
+		},
+		{
+			name: "cycle",
+			obj:  cycle,
+			want: map[string]interface{}{"Name": "a"},
+		},
+		{
+			name: "scalar",
+			obj:  1,
+			want: map[string]interface{}{"": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dipper.New(tt.opts).Flatten(tt.obj)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Flatten() = %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_Unflatten(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    dipper.Options
+		m       map[string]interface{}
+		target  interface{}
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "struct with parsed strings",
+			opts: dipper.Options{TagName: "json"},
+			m: map[string]interface{}{
+				"host":          "localhost",
+				"port":          "8080",
+				"tls.cert":      "cert.pem",
+				"labels.env":    "prod",
+				"routes.0.path": "/",
+				"routes.1.path": "/api",
+			},
+			target: &ServerConfig{},
+			want: &ServerConfig{
+				Host:   "localhost",
+				Port:   8080,
+				TLS:    &TLSConfig{Cert: "cert.pem"},
+				Labels: map[string]string{"env": "prod"},
+				Routes: []Route{{Path: "/"}, {Path: "/api"}},
+			},
+		},
+		{
+			name:   "nested maps",
+			m:      map[string]interface{}{"a.b": 1, "a.c.0": "x", "a.c.1": "y", "d.1": true},
+			target: &map[string]interface{}{},
+			want: &map[string]interface{}{
+				"a": map[string]interface{}{"b": 1, "c": map[string]interface{}{"0": "x", "1": "y"}},
+				"d": map[string]interface{}{"1": true},
+			},
+		},
+		{
+			name:   "existing slices",
+			m:      map[string]interface{}{"a.1": "z", "b.1.Name": "Crime"},
+			target: &map[string]interface{}{"a": []interface{}{"x", "y"}, "b": []Genre{{Name: "Mystery"}, {}}},
+			want:   &map[string]interface{}{"a": []interface{}{"x", "z"}, "b": []Genre{{Name: "Mystery"}, {Name: "Crime"}}},
+		},
+		{
+			name:   "existing slice elements",
+			m:      map[string]interface{}{"Genres.1.Name": "Crime"},
+			target: &Book{Genres: []Genre{{ID: 1, Name: "Mystery"}, {ID: 2}}},
+			want:   &Book{Genres: []Genre{{ID: 1, Name: "Mystery"}, {ID: 2, Name: "Crime"}}},
+		},
+		{
+			name:   "slices are extended",
+			m:      map[string]interface{}{"Genres.2.Name": "Crime", "GenreNames.10": "Mystery"},
+			target: &Book{Genres: []Genre{{Name: "Mystery"}}},
+			want: &Book{
+				Genres:     []Genre{{Name: "Mystery"}, {}, {Name: "Crime"}},
+				GenreNames: append(make([]string, 10), "Mystery"),
+			},
+		},
+		{
+			name:   "bracket notation",
+			m:      map[string]interface{}{"Genres[1].Name": "Crime", "GenreNames[0]": "Mystery"},
+			target: &Book{},
+			want:   &Book{Genres: []Genre{{}, {Name: "Crime"}}, GenreNames: []string{"Mystery"}},
+		},
+		{
+			name:   "filter expression",
+			m:      map[string]interface{}{"Genres[Name='Crime'].ID": "2"},
+			target: &Book{Genres: []Genre{{Name: "Mystery"}, {Name: "Crime"}}},
+			want:   &Book{Genres: []Genre{{Name: "Mystery"}, {ID: 2, Name: "Crime"}}},
+		},
+		{
+			name:   "numeric keys of string maps",
+			opts:   dipper.Options{TagName: "json"},
+			m:      map[string]interface{}{"labels.0": "x"},
+			target: &ServerConfig{},
+			want:   &ServerConfig{Labels: map[string]string{"0": "x"}},
+		},
+		{
+			name:   "integer map keys",
+			m:      map[string]interface{}{"ByID.0": "a", "ByID.1": "b"},
+			target: &Inventory{},
+			want:   &Inventory{ByID: map[int]string{0: "a", 1: "b"}},
+		},
+		{
+			name:   "array elements",
+			m:      map[string]interface{}{"Slots.1": "2", "Slots.2": 3},
+			target: &Inventory{},
+			want:   &Inventory{Slots: [3]int{0, 2, 3}},
+		},
+		{
+			name:    "array index out of range",
+			m:       map[string]interface{}{"Slots.3": 1},
+			target:  &Inventory{},
+			want:    &Inventory{},
+			wantErr: dipper.ErrIndexOutOfRange,
+		},
+		{
+			name:   "structs held by maps and pointers",
+			m:      map[string]interface{}{"Items.a.Path": "/", "Items.a.Backend": "web", "Owner.Name": "Umberto Eco"},
+			target: &Inventory{Items: map[string]Route{"a": {Path: "/old"}}},
+			want: &Inventory{
+				Items: map[string]Route{"a": {Path: "/", Backend: "web"}},
+				Owner: &Author{Name: "Umberto Eco"},
+			},
+		},
+		{
+			name:   "JSON pointer append",
+			opts:   dipper.Options{Syntax: dipper.JSONPointer},
+			m:      map[string]interface{}{"/Tags/-": "x"},
+			target: &Inventory{Tags: []string{"a"}},
+			want:   &Inventory{Tags: []string{"a", "x"}},
+		},
+		{
+			name:   "raw JSON",
+			m:      map[string]interface{}{"Payload.user.id": 8},
+			target: &Event{Payload: json.RawMessage(`{"user": {"id": 7}}`)},
+			want:   &Event{Payload: json.RawMessage(`{"user":{"id":8}}`)},
+		},
+		{
+			name:   "root",
+			m:      map[string]interface{}{"": map[string]interface{}{"Cert": "x"}},
+			target: &TLSConfig{},
+			want:   &TLSConfig{Cert: "x"},
+		},
+		{
+			name:   "JSON pointer syntax",
+			opts:   dipper.Options{Syntax: dipper.JSONPointer},
+			m:      map[string]interface{}{"/a.b/c~1d": 1},
+			target: &map[string]interface{}{},
+			want:   &map[string]interface{}{"a.b": map[string]interface{}{"c/d": 1}},
+		},
+		{
+			name:    "leaf and parent",
+			m:       map[string]interface{}{"a": 1, "a.b": 2},
+			target:  &map[string]interface{}{},
+			want:    &map[string]interface{}{},
+			wantErr: dipper.ErrTypesDoNotMatch,
+		},
+		{
+			name:    "types do not match",
+			m:       map[string]interface{}{"Cert": "x", "Key": []int{1}},
+			target:  &TLSConfig{},
+			want:    &TLSConfig{Cert: "x"},
+			wantErr: dipper.ErrTypesDoNotMatch,
+		},
+		{
+			name:    "unaddressable",
+			m:       map[string]interface{}{"Cert": "x"},
+			target:  TLSConfig{},
+			want:    TLSConfig{},
+			wantErr: dipper.ErrUnaddressable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dipper.New(tt.opts).Unflatten(tt.m, tt.target)
+			if err != tt.wantErr {
+				t.Fatalf("Unflatten() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.target, tt.want) {
+				t.Errorf("Unflatten() target = %#v, want %#v", tt.target, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_Unflatten_RoundTrip(t *testing.T) {
+	book := getTestStruct()
+	got := &Book{}
+	if err := dipper.Unflatten(dipper.Flatten(book), got); err != nil {
+		t.Fatal(err)
+	}
+
+	// Values held by interfaces are created as generic maps
+	want := getTestStruct()
+	want.Extra["foo"] = map[string]interface{}{"bar": 123}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unflatten(Flatten()) = %#v, want %#v", got, want)
+	}
+}
+
+func TestDipper_Unflatten_RoundTripIndexes(t *testing.T) {
+	inventory := &Inventory{
+		Slots:  [3]int{1, 2, 3},
+		ByID:   map[int]string{0: "a", 7: "b"},
+		Counts: map[string]int{"0": 1, "1": 2},
+		Items:  map[string]Route{"0": {Path: "/", Backend: "web"}},
+		Owner:  &Author{Name: "Umberto Eco", BirthDate: mustParseDate("1932-07-05")},
+	}
+	for i := 0; i < 12; i++ {
+		inventory.Tags = append(inventory.Tags, "tag"+strconv.Itoa(i))
+	}
+
+	got := &Inventory{}
+	if err := dipper.Unflatten(dipper.Flatten(inventory), got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, inventory) {
+		t.Errorf("Unflatten(Flatten()) = %#v, want %#v", got, inventory)
+	}
+}
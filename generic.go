@@ -0,0 +1,84 @@
+//go:build go1.21
+// +build go1.21
+
+package dipper
+
+import (
+	"reflect"
+)
+
+// GetAs returns the value of the given obj attribute as a value of type T,
+// using the given Dipper instance (or a default one for dot notation, if d is
+// nil). It returns the errors returned by Dipper.Get(), or a
+// *TypeMismatchError if the value is not of type T. If the ConvertNumbers
+// option is enabled, numeric values are converted to T if it is a numeric type
+// and the value is preserved exactly (e.g. 2.0 to 2, but not 2.5).
+//
+// Example:
+//
+//	year, err := dipper.GetAs[int](my_dipper, book, "Year")
+//	if err != nil {
+//	    return err
+//	}
+func GetAs[T any](d *Dipper, obj interface{}, attribute string) (T, error) {
+	var result T
+	if d == nil {
+		d = defaultDipper
+	}
+
+	v := d.Get(obj, attribute)
+	if err := Error(v); err != nil {
+		return result, err
+	}
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+
+	expected := reflect.TypeOf(&result).Elem()
+	if v == nil {
+		switch expected.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return result, nil
+		}
+		return result, &TypeMismatchError{Path: attribute, Expected: expected}
+	}
+
+	value := reflect.ValueOf(v)
+	if d.opts.ConvertNumbers {
+		if converted, ok := convertNumber(value, expected); ok {
+			return converted.Interface().(T), nil
+		}
+	}
+	return result, &TypeMismatchError{Path: attribute, Expected: expected, Actual: value.Type()}
+}
+
+// MustGet works as GetAs(), but it panics if an error occurs.
+//
+// Example:
+//
+//	title := dipper.MustGet[string](nil, book, "Title")
+func MustGet[T any](d *Dipper, obj interface{}, attribute string) T {
+	v, err := GetAs[T](d, obj, attribute)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// GetOr works as GetAs(), but it returns the given default value if the
+// attribute is not found (ErrNotFound, ErrIndexOutOfRange or
+// ErrFilterNotFound).
+//
+// Example:
+//
+//	port, err := dipper.GetOr(my_dipper, config, "Server.Port", 8080)
+//	if err != nil {
+//	    return err
+//	}
+func GetOr[T any](d *Dipper, obj interface{}, attribute string, def T) (T, error) {
+	v, err := GetAs[T](d, obj, attribute)
+	if isNotFound(err) {
+		return def, nil
+	}
+	return v, err
+}
@@ -0,0 +1,135 @@
+//go:build go1.21
+// +build go1.21
+
+package dipper_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestGetAs(t *testing.T) {
+	book := getTestStruct()
+	jsonBook := toJSONMap(book)
+	convert := dipper.New(dipper.Options{ConvertNumbers: true})
+
+	t.Run("value of the requested type", func(t *testing.T) {
+		got, err := dipper.GetAs[string](nil, book, "Author.Name")
+		if err != nil || got != "Umberto Eco" {
+			t.Errorf("GetAs() = %v, %v, want %v", got, err, "Umberto Eco")
+		}
+	})
+
+	t.Run("interface type", func(t *testing.T) {
+		got, err := dipper.GetAs[interface{}](nil, book, "Year")
+		if err != nil || got != 1980 {
+			t.Errorf("GetAs() = %v, %v, want %v", got, err, 1980)
+		}
+	})
+
+	t.Run("nil value", func(t *testing.T) {
+		got, err := dipper.GetAs[[]string](nil, map[string]interface{}{"a": nil}, "a")
+		if err != nil || got != nil {
+			t.Errorf("GetAs() = %v, %v, want nil", got, err)
+		}
+	})
+
+	t.Run("get error", func(t *testing.T) {
+		_, err := dipper.GetAs[string](nil, book, "Author.Surname")
+		if err != dipper.ErrNotFound {
+			t.Errorf("GetAs() error = %v, want %v", err, dipper.ErrNotFound)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		d       *dipper.Dipper
+		obj     interface{}
+		attr    string
+		want    int
+		wantErr *dipper.TypeMismatchError
+	}{
+		{
+			name: "type mismatch", obj: jsonBook, attr: "year",
+			wantErr: &dipper.TypeMismatchError{Path: "year", Expected: reflect.TypeOf(0), Actual: reflect.TypeOf(0.0)},
+		},
+		{
+			name: "nil value mismatch", obj: map[string]interface{}{"a": nil}, attr: "a",
+			wantErr: &dipper.TypeMismatchError{Path: "a", Expected: reflect.TypeOf(0)},
+		},
+		{name: "converted number", d: convert, obj: jsonBook, attr: "year", want: 1980},
+		{name: "converted integer", d: convert, obj: map[string]int8{"a": -3}, attr: "a", want: -3},
+		{
+			name: "number that cannot be converted", d: convert, obj: map[string]float64{"a": 2.5}, attr: "a",
+			wantErr: &dipper.TypeMismatchError{Path: "a", Expected: reflect.TypeOf(0), Actual: reflect.TypeOf(0.0)},
+		},
+		{
+			name: "non-numeric value", d: convert, obj: book, attr: "Title",
+			wantErr: &dipper.TypeMismatchError{Path: "Title", Expected: reflect.TypeOf(0), Actual: reflect.TypeOf("")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dipper.GetAs[int](tt.d, tt.obj, tt.attr)
+			if tt.wantErr == nil {
+				if err != nil || got != tt.want {
+					t.Errorf("GetAs() = %v, %v, want %v", got, err, tt.want)
+				}
+				return
+			}
+
+			var mismatch *dipper.TypeMismatchError
+			if !errors.As(err, &mismatch) || !reflect.DeepEqual(mismatch, tt.wantErr) {
+				t.Fatalf("GetAs() error = %v, want %v", err, tt.wantErr)
+			}
+			if !errors.Is(err, dipper.ErrTypesDoNotMatch) {
+				t.Errorf("GetAs() error does not match %v", dipper.ErrTypesDoNotMatch)
+			}
+		})
+	}
+}
+
+func TestMustGet(t *testing.T) {
+	if got := dipper.MustGet[int](nil, getTestStruct(), "Year"); got != 1980 {
+		t.Errorf("MustGet() = %v, want %v", got, 1980)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("MustGet() did not panic")
+		}
+	}()
+	dipper.MustGet[string](nil, getTestStruct(), "Year")
+}
+
+func TestGetOr(t *testing.T) {
+	book := getTestStruct()
+
+	tests := []struct {
+		name    string
+		attr    string
+		want    string
+		wantErr error
+	}{
+		{name: "found", attr: "Title", want: "El nombre de la rosa"},
+		{name: "field not found", attr: "Subtitle", want: "default"},
+		{name: "index out of range", attr: "GenreNames.5", want: "default"},
+		{name: "filter not found", attr: "Genres[Name='Horror'].Name", want: "default"},
+		{name: "type mismatch", attr: "Year", wantErr: dipper.ErrTypesDoNotMatch},
+		{name: "invalid index", attr: "GenreNames.x", wantErr: dipper.ErrInvalidIndex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dipper.GetOr(nil, book, tt.attr, "default")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("GetOr() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetOr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
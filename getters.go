@@ -0,0 +1,249 @@
+package dipper
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var (
+	stringType   = reflect.TypeOf("")
+	intType      = reflect.TypeOf(0)
+	boolType     = reflect.TypeOf(false)
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// GetString returns the value of the given obj attribute as a string. Strings
+// and []byte values are returned as they are, values implementing
+// encoding.TextMarshaler or fmt.Stringer are formatted with them, and numbers
+// and booleans are formatted as in fmt.Sprint().
+// If the attribute cannot be accessed, the error returned by Dipper.Get() is
+// returned in a *PathError. If the value cannot be converted, a
+// *TypeMismatchError is returned.
+//
+// Example:
+//
+//	name, err := my_dipper.GetString(config, "Server.Name")
+//	if err != nil {
+//	    return err
+//	}
+func (d *Dipper) GetString(obj interface{}, attribute string) (string, error) {
+	value, err := d.getTyped(obj, attribute)
+	if err != nil {
+		return "", err
+	}
+
+	if value.IsValid() {
+		if value.CanInterface() {
+			switch v := value.Interface().(type) {
+			case encoding.TextMarshaler:
+				if text, err := v.MarshalText(); err == nil {
+					return string(text), nil
+				}
+			case fmt.Stringer:
+				return v.String(), nil
+			}
+		}
+
+		switch kind := value.Kind(); {
+		case kind == reflect.String:
+			return value.String(), nil
+		case kind == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+			return string(value.Bytes()), nil
+		case kind == reflect.Bool || isNumberKind(kind):
+			return fmt.Sprint(value.Interface()), nil
+		}
+	}
+	return "", typeMismatch(attribute, stringType, value)
+}
+
+// GetInt returns the value of the given obj attribute as an int. Numbers are
+// converted if they are integral and fit in an int (e.g. a float64 decoded
+// from JSON), and strings are parsed as base 10 integers.
+// It returns the same errors as Dipper.GetString().
+//
+// Example:
+//
+//	port, err := my_dipper.GetInt(config, "Server.Port")
+//	if err != nil {
+//	    return err
+//	}
+func (d *Dipper) GetInt(obj interface{}, attribute string) (int, error) {
+	value, err := d.getTyped(obj, attribute)
+	if err != nil {
+		return 0, err
+	}
+
+	if converted, ok := convertNumber(value, intType); ok {
+		return int(converted.Int()), nil
+	}
+	if value.Kind() == reflect.String {
+		if n, err := strconv.ParseInt(value.String(), 10, 0); err == nil {
+			return int(n), nil
+		}
+	}
+	return 0, typeMismatch(attribute, intType, value)
+}
+
+// GetBool returns the value of the given obj attribute as a bool. Strings are
+// parsed as in strconv.ParseBool() (e.g. "true", "1" or "F").
+// It returns the same errors as Dipper.GetString().
+//
+// Example:
+//
+//	enabled, err := my_dipper.GetBool(config, "Server.TLS.Enabled")
+//	if err != nil {
+//	    return err
+//	}
+func (d *Dipper) GetBool(obj interface{}, attribute string) (bool, error) {
+	value, err := d.getTyped(obj, attribute)
+	if err != nil {
+		return false, err
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.String:
+		if b, err := strconv.ParseBool(value.String()); err == nil {
+			return b, nil
+		}
+	}
+	return false, typeMismatch(attribute, boolType, value)
+}
+
+// GetDuration returns the value of the given obj attribute as a
+// time.Duration. Strings are parsed as in time.ParseDuration() (e.g. "30s"),
+// and integral numbers are taken as nanoseconds, as time.Duration values are
+// encoded in JSON.
+// It returns the same errors as Dipper.GetString().
+//
+// Example:
+//
+//	timeout, err := my_dipper.GetDuration(config, "Server.Timeout")
+//	if err != nil {
+//	    return err
+//	}
+func (d *Dipper) GetDuration(obj interface{}, attribute string) (time.Duration, error) {
+	value, err := d.getTyped(obj, attribute)
+	if err != nil {
+		return 0, err
+	}
+
+	if converted, ok := convertNumber(value, durationType); ok {
+		return time.Duration(converted.Int()), nil
+	}
+	if value.Kind() == reflect.String {
+		if duration, err := time.ParseDuration(value.String()); err == nil {
+			return duration, nil
+		}
+	}
+	return 0, typeMismatch(attribute, durationType, value)
+}
+
+// GetTime returns the value of the given obj attribute as a time.Time.
+// Strings are parsed using the layouts of the TimeLayouts option, in order.
+// It returns the same errors as Dipper.GetString().
+//
+// Example:
+//
+//	published, err := my_dipper.GetTime(book, "Publication.Date")
+//	if err != nil {
+//	    return err
+//	}
+func (d *Dipper) GetTime(obj interface{}, attribute string) (time.Time, error) {
+	value, err := d.getTyped(obj, attribute)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if value.IsValid() && value.Type().ConvertibleTo(timeType) && value.Kind() == reflect.Struct {
+		return value.Convert(timeType).Interface().(time.Time), nil
+	}
+	if value.Kind() == reflect.String {
+		for _, layout := range d.opts.TimeLayouts {
+			if t, err := time.Parse(layout, value.String()); err == nil {
+				return t, nil
+			}
+		}
+	}
+	return time.Time{}, typeMismatch(attribute, timeType, value)
+}
+
+// GetInto decodes the value of the given obj attribute into target, which must
+// be a non-nil pointer. Map keys and struct fields are decoded into the target
+// struct fields with the same name (e.g. according to the TagName option),
+// ignoring the ones with no matching field, and values are converted to the
+// target types if needed (e.g. a float64 decoded from JSON to an int), as in
+// Dipper.Merge(). Nil values set the target values to their zero value.
+// If the attribute cannot be accessed, the error returned by Dipper.Get() is
+// returned in a *PathError. If a value cannot be decoded, the error is
+// returned in a *PathError with the full attribute of the value (e.g.
+// "Config.Servers.0.Port").
+//
+// Example:
+//
+//	var server ServerConfig
+//	if err := my_dipper.GetInto(config, "Services.api.Server", &server); err != nil {
+//	    return err
+//	}
+func (d *Dipper) GetInto(obj interface{}, attribute string, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return ErrUnaddressable
+	}
+
+	v, err := d.getWithPath(obj, attribute)
+	if err != nil {
+		return err
+	}
+
+	m := merger{d: d, opts: MergeOptions{NilDeletes: true}, ignoreUnknown: true, reportPaths: true}
+	if v == nil {
+		return m.fail(m.setConverted(value.Elem(), reflect.Zero(value.Elem().Type())), attribute)
+	}
+	return m.merge(value.Elem(), reflect.ValueOf(v), attribute)
+}
+
+// getTyped returns the value of the given obj attribute to be converted by the
+// typed getters, following pointers and interfaces. The value is invalid if it
+// is nil. Errors are returned in a *PathError with the attribute.
+func (d *Dipper) getTyped(obj interface{}, attribute string) (reflect.Value, error) {
+	v, err := d.getWithPath(obj, attribute)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	value := getElemSafe(reflect.ValueOf(v))
+	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		return reflect.Value{}, nil
+	}
+	return value, nil
+}
+
+// getWithPath returns the value of the given obj attribute, or the error
+// returned by Dipper.Get() in a *PathError with the attribute.
+func (d *Dipper) getWithPath(obj interface{}, attribute string) (interface{}, error) {
+	v := d.Get(obj, attribute)
+	err := Error(v)
+	if err == nil {
+		return v, nil
+	}
+	if _, ok := err.(*PathError); ok {
+		return nil, err
+	}
+	return nil, &PathError{Path: attribute, Err: err}
+}
+
+// typeMismatch returns a *TypeMismatchError for the given value, which is
+// invalid if it is nil.
+func typeMismatch(attribute string, expected reflect.Type, value reflect.Value) error {
+	err := &TypeMismatchError{Path: attribute, Expected: expected}
+	if value.IsValid() {
+		err.Actual = value.Type()
+	}
+	return err
+}
@@ -0,0 +1,289 @@
+package dipper_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/flusflas/dipper"
+)
+
+// getterObject returns an object with values of several types for the typed
+// getters tests.
+func getterObject() map[string]interface{} {
+	return map[string]interface{}{
+		"string":    "text",
+		"bytes":     []byte("bytes"),
+		"int":       42,
+		"int8":      int8(-8),
+		"intPtr":    intPtr(7),
+		"float":     3.0,
+		"fraction":  2.5,
+		"negative":  -1.0,
+		"bool":      true,
+		"nil":       nil,
+		"numeric":   "123",
+		"boolean":   "false",
+		"duration":  "1m30s",
+		"durationN": time.Second,
+		"date":      "2020-05-17",
+		"datetime":  "2020-05-17T10:30:00Z",
+		"time":      mustParseDate("1932-07-05"),
+		"slice":     []int{1},
+	}
+}
+
+func TestDipper_GetString(t *testing.T) {
+	tests := []struct {
+		attribute string
+		want      string
+		wantErr   bool
+	}{
+		{attribute: "string", want: "text"},
+		{attribute: "bytes", want: "bytes"},
+		{attribute: "int", want: "42"},
+		{attribute: "fraction", want: "2.5"},
+		{attribute: "bool", want: "true"},
+		{attribute: "durationN", want: "1s"},
+		{attribute: "time", want: "1932-07-05T00:00:00Z"},
+		{attribute: "slice", wantErr: true},
+		{attribute: "nil", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.attribute, func(t *testing.T) {
+			got, err := dipper.GetString(getterObject(), tt.attribute)
+			checkGetterError(t, err, tt.wantErr, tt.attribute, reflect.TypeOf(""))
+			if got != tt.want {
+				t.Errorf("GetString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_GetInt(t *testing.T) {
+	tests := []struct {
+		attribute string
+		want      int
+		wantErr   bool
+	}{
+		{attribute: "int", want: 42},
+		{attribute: "int8", want: -8},
+		{attribute: "intPtr", want: 7},
+		{attribute: "float", want: 3},
+		{attribute: "negative", want: -1},
+		{attribute: "numeric", want: 123},
+		{attribute: "fraction", wantErr: true},
+		{attribute: "string", wantErr: true},
+		{attribute: "bool", wantErr: true},
+		{attribute: "nil", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.attribute, func(t *testing.T) {
+			got, err := dipper.GetInt(getterObject(), tt.attribute)
+			checkGetterError(t, err, tt.wantErr, tt.attribute, reflect.TypeOf(0))
+			if got != tt.want {
+				t.Errorf("GetInt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_GetBool(t *testing.T) {
+	tests := []struct {
+		attribute string
+		want      bool
+		wantErr   bool
+	}{
+		{attribute: "bool", want: true},
+		{attribute: "boolean", want: false},
+		{attribute: "string", wantErr: true},
+		{attribute: "int", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.attribute, func(t *testing.T) {
+			got, err := dipper.GetBool(getterObject(), tt.attribute)
+			checkGetterError(t, err, tt.wantErr, tt.attribute, reflect.TypeOf(false))
+			if got != tt.want {
+				t.Errorf("GetBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_GetDuration(t *testing.T) {
+	tests := []struct {
+		attribute string
+		want      time.Duration
+		wantErr   bool
+	}{
+		{attribute: "duration", want: 90 * time.Second},
+		{attribute: "durationN", want: time.Second},
+		{attribute: "int", want: 42},
+		{attribute: "string", wantErr: true},
+		{attribute: "fraction", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.attribute, func(t *testing.T) {
+			got, err := dipper.GetDuration(getterObject(), tt.attribute)
+			checkGetterError(t, err, tt.wantErr, tt.attribute, reflect.TypeOf(time.Duration(0)))
+			if got != tt.want {
+				t.Errorf("GetDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_GetTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		attribute string
+		want      time.Time
+		wantErr   bool
+	}{
+		{name: "time", attribute: "time", want: mustParseDate("1932-07-05")},
+		{name: "date", attribute: "date", want: mustParseDate("2020-05-17")},
+		{name: "date time", attribute: "datetime", want: time.Date(2020, 5, 17, 10, 30, 0, 0, time.UTC)},
+		{name: "custom layouts", opts: dipper.Options{TimeLayouts: []string{"2006-01-02"}}, attribute: "datetime", wantErr: true},
+		{name: "string", attribute: "string", wantErr: true},
+		{name: "int", attribute: "int", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dipper.New(tt.opts).GetTime(getterObject(), tt.attribute)
+			checkGetterError(t, err, tt.wantErr, tt.attribute, reflect.TypeOf(time.Time{}))
+			if !got.Equal(tt.want) {
+				t.Errorf("GetTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_GetString_NotFound(t *testing.T) {
+	_, err := dipper.GetString(getterObject(), "missing")
+
+	var pathErr *dipper.PathError
+	if !errors.As(err, &pathErr) || pathErr.Path != "missing" || pathErr.Err != dipper.ErrNotFound {
+		t.Errorf("GetString() error = %v, want %v at %q", err, dipper.ErrNotFound, "missing")
+	}
+}
+
+func checkGetterError(t *testing.T, err error, wantErr bool, attribute string, expected reflect.Type) {
+	t.Helper()
+	if !wantErr {
+		if err != nil {
+			t.Fatalf("error = %v, want nil", err)
+		}
+		return
+	}
+
+	var mismatch *dipper.TypeMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Path != attribute || mismatch.Expected != expected {
+		t.Fatalf("error = %v, want a *TypeMismatchError at %q", err, attribute)
+	}
+}
+
+func TestDipper_GetInto(t *testing.T) {
+	jsonConfig := func() map[string]interface{} {
+		return toJSONMap(map[string]interface{}{
+			"services": map[string]interface{}{
+				"api": map[string]interface{}{
+					"host":    "localhost",
+					"port":    8080,
+					"tls":     map[string]interface{}{"cert": "cert.pem"},
+					"labels":  map[string]interface{}{"env": "prod"},
+					"routes":  []interface{}{map[string]interface{}{"path": "/", "backend": "web"}},
+					"unknown": true,
+				},
+				"bad": map[string]interface{}{
+					"routes": []interface{}{map[string]interface{}{"path": "/"}, map[string]interface{}{"path": 1}},
+				},
+				"fraction": map[string]interface{}{"port": 1.5},
+				"none":     nil,
+			},
+		})
+	}
+	d := dipper.New(dipper.Options{TagName: "json"})
+
+	tests := []struct {
+		name      string
+		attribute string
+		target    *ServerConfig
+		want      *ServerConfig
+		wantErr   error
+		wantPath  string
+	}{
+		{
+			name:      "map into struct",
+			attribute: "services.api",
+			target:    &ServerConfig{Timeout: time.Second},
+			want: &ServerConfig{
+				Host:    "localhost",
+				Port:    8080,
+				Timeout: time.Second,
+				TLS:     &TLSConfig{Cert: "cert.pem"},
+				Labels:  map[string]string{"env": "prod"},
+				Routes:  []Route{{Path: "/", Backend: "web"}},
+			},
+		},
+		{
+			name:      "nil value",
+			attribute: "services.none",
+			target:    &ServerConfig{Host: "localhost"},
+			want:      &ServerConfig{},
+		},
+		{
+			name:      "nested field error",
+			attribute: "services.bad",
+			target:    &ServerConfig{},
+			want:      &ServerConfig{},
+			wantErr:   dipper.ErrTypesDoNotMatch,
+			wantPath:  "services.bad.routes.1.path",
+		},
+		{
+			name:      "number that cannot be converted",
+			attribute: "services.fraction",
+			target:    &ServerConfig{},
+			want:      &ServerConfig{},
+			wantErr:   dipper.ErrTypesDoNotMatch,
+			wantPath:  "services.fraction.port",
+		},
+		{
+			name:      "attribute not found",
+			attribute: "services.web",
+			target:    &ServerConfig{},
+			want:      &ServerConfig{},
+			wantErr:   dipper.ErrNotFound,
+			wantPath:  "services.web",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := d.GetInto(jsonConfig(), tt.attribute, tt.target)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("GetInto() error = %v", err)
+				}
+				if !reflect.DeepEqual(tt.target, tt.want) {
+					t.Errorf("GetInto() target = %#v, want %#v", tt.target, tt.want)
+				}
+				return
+			}
+
+			var pathErr *dipper.PathError
+			if !errors.As(err, &pathErr) || pathErr.Err != tt.wantErr || pathErr.Path != tt.wantPath {
+				t.Errorf("GetInto() error = %v, want %v at %q", err, tt.wantErr, tt.wantPath)
+			}
+		})
+	}
+
+	if err := dipper.GetInto(getTestStruct(), "Author", Author{}); err != dipper.ErrUnaddressable {
+		t.Errorf("GetInto() error = %v, want %v", err, dipper.ErrUnaddressable)
+	}
+
+	var author Author
+	if err := dipper.GetInto(getTestStruct(), "Author", &author); err != nil || author != getTestStruct().Author {
+		t.Errorf("GetInto() = %v, %v, want %v", author, err, getTestStruct().Author)
+	}
+}
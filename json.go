@@ -0,0 +1,262 @@
+package dipper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// GetJSON returns the value of the given attribute in the JSON document data,
+// using the same attribute notation as Dipper.Get(). Instead of unmarshalling
+// the whole document, it reads the document tokens until the attribute is
+// found, skipping the irrelevant values, and only decodes the selected value.
+// Values are decoded as they would be by json.Unmarshal() into an interface{}.
+// If an error occurs, it will be returned as the attribute value, so it should
+// be handled. Errors in the JSON document are returned as *PathError, with the
+// path of the value that is not valid, or the whole attribute if the document
+// root is not valid. If the attribute is empty, they are returned as they are.
+//
+// Example:
+//
+//	 // Using "." as the Dipper separator
+//		v := my_dipper.GetJSON(body, "data.user.id")
+//		if err := Error(v); err != nil {
+//		    return err
+//		}
+func (d *Dipper) GetJSON(data []byte, attribute string) interface{} {
+	value, err := d.getJSONValue(json.NewDecoder(bytes.NewReader(data)), attribute)
+	if pathErr, ok := err.(*PathError); ok && pathErr.Path == "" {
+		// The document root is not valid, so the error is reported for the
+		// whole attribute
+		if attribute == "" {
+			return pathErr.Err
+		}
+		pathErr.Path = attribute
+	}
+	if err != nil {
+		return err
+	}
+	return value
+}
+
+// getJSONValue reads the JSON document from the given decoder and returns the
+// value of the attribute.
+func (d *Dipper) getJSONValue(dec *json.Decoder, attribute string) (interface{}, error) {
+	splitter, err := d.newSplitter(attribute)
+	if err != nil {
+		return nil, err
+	}
+
+	// pending is a token read but not processed yet
+	var pending json.Token
+
+	for attribute != "" && splitter.HasMore() {
+		path := splitter.Parsed()
+		fieldName, i := splitter.Next()
+
+		tok := pending
+		pending = nil
+		if tok == nil {
+			var err error
+			if tok, err = readJSONToken(dec, path); err != nil {
+				return nil, err
+			}
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			buffered, err := d.findJSONKey(dec, fieldName, path)
+			if err != nil {
+				return nil, err
+			}
+			if buffered != nil {
+				dec = json.NewDecoder(bytes.NewReader(buffered))
+			}
+
+		case json.Delim('['):
+			if d.opts.Syntax == JSONPointer {
+				index, err := pointerIndex(fieldName)
+				if err != nil {
+					return nil, err
+				}
+				if err := skipJSONValues(dec, index, path); err != nil {
+					return nil, err
+				}
+				break
+			}
+
+			// Ignores field if it is the first one and it is empty. This
+			// happens when using brackets on a root slice (e.g. "[1].Name").
+			if i == 0 && fieldName == "" {
+				pending = tok
+				break
+			}
+
+			if strings.HasPrefix(fieldName, "[") && strings.HasSuffix(fieldName, "]") {
+				fieldName = fieldName[1 : len(fieldName)-1]
+
+				// Filter expressions need the decoded elements, so the rest of
+				// the attribute is resolved on the matching element
+				if strings.Contains(fieldName, "=") {
+					return d.filterJSONArray(dec, fieldName, splitter.Remaining(), path)
+				}
+			}
+
+			index, err := strconv.Atoi(fieldName)
+			if err != nil {
+				return nil, ErrInvalidIndex
+			}
+			if index < 0 {
+				return nil, ErrIndexOutOfRange
+			}
+			if err := skipJSONValues(dec, index, path); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, ErrNotFound
+		}
+	}
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, &PathError{Path: attribute, Err: err}
+	}
+	return value, nil
+}
+
+// findJSONKey reads the members of the current JSON object from the decoder
+// until the key accessed by name is found, so the next value in the decoder is
+// the value of that key. It returns ErrNotFound if the object has no such key,
+// or a *PathError if the object is not valid (e.g. a truncated document).
+// If the CaseInsensitive option is enabled and the key is not an exact match,
+// the whole object must be read to check that the key is not ambiguous. In
+// that case, the raw value of the key is returned to be read instead of the
+// decoder.
+func (d *Dipper) findJSONKey(dec *json.Decoder, name, path string) ([]byte, error) {
+	var match json.RawMessage
+	matches := 0
+
+	for dec.More() {
+		tok, err := readJSONToken(dec, path)
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+
+		if key == name {
+			return nil, nil
+		}
+
+		if d.opts.CaseInsensitive && strings.EqualFold(key, name) {
+			matches++
+			if err := dec.Decode(&match); err != nil {
+				return nil, &PathError{Path: path, Err: err}
+			}
+			continue
+		}
+
+		if err := skipJSONValue(dec, path); err != nil {
+			return nil, err
+		}
+	}
+
+	// The object must be closed, otherwise the document is not valid
+	if _, err := readJSONToken(dec, path); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case matches > 1:
+		return nil, ErrAmbiguousField
+	case matches == 1:
+		return match, nil
+	default:
+		return nil, ErrNotFound
+	}
+}
+
+// filterJSONArray decodes the elements of the current JSON array from the
+// decoder until one of them matches the given filter expression, and returns
+// the value of the remaining attribute in that element.
+func (d *Dipper) filterJSONArray(dec *json.Decoder, filter, remaining, path string) (interface{}, error) {
+	for dec.More() {
+		var elem interface{}
+		if err := dec.Decode(&elem); err != nil {
+			return nil, &PathError{Path: path, Err: err}
+		}
+
+		match, err := d.filterSlice(reflect.ValueOf([]interface{}{elem}), filter)
+		if err == ErrFilterNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.getReflectValue(match, remaining, nil)
+		if err != nil {
+			return nil, err
+		}
+		return value.Interface(), nil
+	}
+	return nil, ErrFilterNotFound
+}
+
+// readJSONToken returns the next token from the decoder. Errors are returned
+// as *PathError using the given path.
+func readJSONToken(dec *json.Decoder, path string) (json.Token, error) {
+	tok, err := dec.Token()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, &PathError{Path: path, Err: err}
+	}
+	return tok, nil
+}
+
+// skipJSONValues reads the given number of values of the current JSON array
+// from the decoder, so the next value in the decoder is the element at that
+// index. It returns ErrIndexOutOfRange if the array has no such element, or a
+// *PathError if the array is not valid.
+func skipJSONValues(dec *json.Decoder, n int, path string) error {
+	for ; n > 0 && dec.More(); n-- {
+		if err := skipJSONValue(dec, path); err != nil {
+			return err
+		}
+	}
+	if !dec.More() {
+		// The array must be closed, otherwise the document is not valid
+		if _, err := readJSONToken(dec, path); err != nil {
+			return err
+		}
+		return ErrIndexOutOfRange
+	}
+	return nil
+}
+
+// skipJSONValue reads the next value from the decoder without decoding it.
+func skipJSONValue(dec *json.Decoder, path string) error {
+	depth := 0
+	for {
+		tok, err := readJSONToken(dec, path)
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
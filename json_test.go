@@ -0,0 +1,184 @@
+package dipper_test
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+func getTestJSON() []byte {
+	data, err := json.Marshal(map[string]interface{}{
+		"book":    getTestStruct(),
+		"ignored": []interface{}{map[string]interface{}{"a": []int{1, 2}}, "[{"},
+		"list":    []interface{}{1, "two", []int{3}},
+		"Name":    "upper",
+		"name":    "lower",
+		"Title":   "title",
+	})
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func TestDipper_GetJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		data      []byte
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "whole document",
+			data:      []byte(`{"a": [1, 2]}`),
+			attribute: "",
+			want:      map[string]interface{}{"a": []interface{}{1.0, 2.0}},
+		},
+		{
+			name:      "nested value",
+			data:      getTestJSON(),
+			attribute: "book.author.name",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "object",
+			data:      getTestJSON(),
+			attribute: "book.extra",
+			want:      map[string]interface{}{"foo": map[string]interface{}{"bar": 123.0}},
+		},
+		{
+			name:      "array element",
+			data:      getTestJSON(),
+			attribute: "list.2.0",
+			want:      3.0,
+		},
+		{
+			name:      "array element with brackets",
+			data:      getTestJSON(),
+			attribute: "book.genre_names[1]",
+			want:      "Crime",
+		},
+		{
+			name:      "root array with brackets",
+			data:      []byte(`[{"a": 1}, {"a": 2}]`),
+			attribute: "[1].a",
+			want:      2.0,
+		},
+		{
+			name:      "filter",
+			data:      getTestJSON(),
+			attribute: "book.genres[name='Crime'].id",
+			want:      1.0,
+		},
+		{
+			name:      "filter with separator",
+			opts:      dipper.Options{Separator: "/"},
+			data:      getTestJSON(),
+			attribute: "book/genres[id=1]/name",
+			want:      "Crime",
+		},
+		{
+			name:      "filter not found",
+			data:      getTestJSON(),
+			attribute: "book.genres[name='Romance']",
+			want:      dipper.ErrFilterNotFound,
+		},
+		{
+			name:      "key not found",
+			data:      getTestJSON(),
+			attribute: "book.author.email",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "field of scalar",
+			data:      getTestJSON(),
+			attribute: "book.title.foo",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "index out of range",
+			data:      getTestJSON(),
+			attribute: "list.3",
+			want:      dipper.ErrIndexOutOfRange,
+		},
+		{
+			name:      "invalid index",
+			data:      getTestJSON(),
+			attribute: "list.x",
+			want:      dipper.ErrInvalidIndex,
+		},
+		{
+			name:      "case-insensitive exact match",
+			opts:      dipper.Options{CaseInsensitive: true},
+			data:      getTestJSON(),
+			attribute: "name",
+			want:      "lower",
+		},
+		{
+			name:      "case-insensitive match",
+			opts:      dipper.Options{CaseInsensitive: true},
+			data:      getTestJSON(),
+			attribute: "BOOK.Author.Name",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "case-insensitive ambiguous key",
+			opts:      dipper.Options{CaseInsensitive: true},
+			data:      getTestJSON(),
+			attribute: "NAME",
+			want:      dipper.ErrAmbiguousField,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(tt.opts)
+			got := d.GetJSON(tt.data, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJSON(t *testing.T) {
+	if got := dipper.GetJSON(getTestJSON(), "book.year"); got != 1980.0 {
+		t.Errorf("GetJSON() = %v, want %v", got, 1980.0)
+	}
+
+	got := dipper.GetJSON([]byte(`{"a": {"b": [1, }}`), "a.c")
+	if _, ok := got.(*dipper.PathError); !ok {
+		t.Errorf("GetJSON() = %v, want *PathError", got)
+	}
+
+	got = dipper.GetJSON([]byte(`{"a": `), "a")
+	if _, ok := got.(*dipper.PathError); !ok {
+		t.Errorf("GetJSON() = %v, want *PathError", got)
+	}
+
+	// Truncated or malformed documents are not reported as missing values
+	for data, attribute := range map[string]string{
+		`{"a": {"b": 1`:          "a.c",
+		`{"a": [1, 2`:            "a.5",
+		`{"a": {"b": 1 "c": 2}}`: "a.c",
+	} {
+		got = dipper.GetJSON([]byte(data), attribute)
+		if _, ok := got.(*dipper.PathError); !ok {
+			t.Errorf("GetJSON(%s, %q) = %v, want *PathError", data, attribute, got)
+		}
+	}
+
+	// Errors at the document root are reported with the whole attribute
+	for _, data := range []string{``, `{`, `{"b": 1,`, `x`} {
+		got = dipper.GetJSON([]byte(data), "a.b")
+		if err, ok := got.(*dipper.PathError); !ok || err.Path != "a.b" {
+			t.Errorf("GetJSON(%s, %q) = %v, want *PathError with path a.b", data, "a.b", got)
+		}
+	}
+	if got = dipper.GetJSON([]byte(`{"a": `), ""); got != io.ErrUnexpectedEOF {
+		t.Errorf("GetJSON(%s, %q) = %v, want %v", `{"a": `, "", got, io.ErrUnexpectedEOF)
+	}
+}
@@ -0,0 +1,450 @@
+package dipper
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SetJSON sets the value of the given attribute in the JSON document data and
+// returns the modified document, using the same attribute notation as
+// Dipper.Set(). Only the bytes of the modified value are replaced, so the
+// whitespace, key order and number formatting of the rest of the document are
+// kept as they were.
+// If the last field of the attribute is a key that does not exist in an
+// object, the key is added after the last member of the object, following the
+// indentation and spacing of the other members. Using the JSONPointer syntax,
+// a new element can also be appended to an array with the "-" field. If the
+// new value is Delete, the attribute is deleted (see Dipper.DeleteJSON()), and
+// if it is Zero, it is set to null.
+//
+// Example:
+//
+//	 // Using "." as the Dipper separator
+//		config, err := my_dipper.SetJSON(config, "server.port", 8080)
+//		if err != nil {
+//		    return err
+//		}
+func (d *Dipper) SetJSON(data []byte, attribute string, value interface{}) ([]byte, error) {
+	switch value {
+	case Delete:
+		return d.DeleteJSON(data, attribute)
+	case Zero:
+		value = nil
+	}
+
+	loc, err := d.locateJSON(data, attribute)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := loc.parent
+	if loc.index >= 0 {
+		encoded, err := marshalJSONValue(value, data, parent)
+		if err != nil {
+			return nil, err
+		}
+		return replaceBytes(data, loc.start, loc.end, encoded), nil
+	}
+
+	// Add a new key to the parent object, or a new element to the parent array
+	encoded, err := marshalJSONValue(value, data, parent)
+	if err != nil {
+		return nil, err
+	}
+	var key []byte
+	if parent.object {
+		if key, err = json.Marshal(loc.key); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(parent.members) == 0 {
+		var member []byte
+		if parent.object {
+			member = append(key, ':')
+		}
+		member = append(member, encoded...)
+		return replaceBytes(data, parent.start+1, parent.start+1, member), nil
+	}
+
+	// The new member is separated from the previous one and written like the
+	// last member of the container
+	n := len(parent.members)
+	last := parent.members[n-1]
+
+	var member []byte
+	if n > 1 {
+		member = append(member, data[parent.members[n-2].valueEnd:last.keyStart]...)
+	} else {
+		member = append(member, ',')
+		space := data[parent.start+1 : last.keyStart]
+		if len(space) == 0 {
+			// There is no spacing to copy between members, so the spacing
+			// after the colon is used for objects, and a space for arrays
+			space = []byte(" ")
+			if parent.object {
+				sep := data[last.keyEnd:last.valueStart]
+				space = sep[bytes.IndexByte(sep, ':')+1:]
+			}
+		}
+		member = append(member, space...)
+	}
+	if parent.object {
+		member = append(member, key...)
+		member = append(member, data[last.keyEnd:last.valueStart]...)
+	}
+	member = append(member, encoded...)
+	return replaceBytes(data, last.valueEnd, last.valueEnd, member), nil
+}
+
+// DeleteJSON deletes the given attribute from the JSON document data and
+// returns the modified document, using the same attribute notation as
+// Dipper.Set(). The attribute must reference an object member or an array
+// element. Only the bytes of the deleted member or element (and its separating
+// comma) are removed, so the rest of the document is kept as it was.
+//
+// Example:
+//
+//	 // Using "." as the Dipper separator
+//		config, err := my_dipper.DeleteJSON(config, "server.debug")
+//		if err != nil {
+//		    return err
+//		}
+func (d *Dipper) DeleteJSON(data []byte, attribute string) ([]byte, error) {
+	loc, err := d.locateJSON(data, attribute)
+	if err != nil {
+		return nil, err
+	}
+	if loc.parent == nil || loc.index < 0 {
+		return nil, ErrNotFound
+	}
+
+	parent := loc.parent
+	members := parent.members
+	k := loc.index
+
+	switch {
+	case len(members) == 1:
+		return replaceBytes(data, parent.start+1, parent.end, nil), nil
+	case k < len(members)-1:
+		return replaceBytes(data, members[k].keyStart, members[k+1].keyStart, nil), nil
+	default:
+		return replaceBytes(data, members[k-1].valueEnd, members[k].valueEnd, nil), nil
+	}
+}
+
+// jsonContainer holds the offsets of a JSON object or array and its members.
+type jsonContainer struct {
+	start   int // offset of the opening delimiter
+	end     int // offset of the closing delimiter
+	object  bool
+	members []jsonMember
+}
+
+// jsonMember holds the offsets of an object member or an array element. For
+// array elements, the key offsets are the same as the value start.
+type jsonMember struct {
+	key        string
+	keyStart   int
+	keyEnd     int
+	valueStart int
+	valueEnd   int
+}
+
+// jsonLocation is the location of an attribute value in a JSON document.
+type jsonLocation struct {
+	// start and end are the offsets of the value
+	start, end int
+	// parent is the object or array containing the value, or nil for the
+	// root value
+	parent *jsonContainer
+	// index is the index of the value in its parent, or -1 if the value is a
+	// key that does not exist in the parent object or the position after the
+	// last element of the parent array
+	index int
+	// key is the name of the key that does not exist in the parent object
+	key string
+}
+
+// locateJSON returns the location of the given attribute in the JSON document
+// data. If the last field of the attribute is a key that does not exist in an
+// object, the returned location has an index of -1.
+func (d *Dipper) locateJSON(data []byte, attribute string) (jsonLocation, error) {
+	if !json.Valid(data) {
+		return jsonLocation{}, ErrInvalidJSON
+	}
+
+	start := skipJSONSpace(data, 0)
+	loc := jsonLocation{start: start, end: scanJSONValue(data, start)}
+
+	if attribute == "" {
+		return loc, nil
+	}
+
+	splitter, err := d.newSplitter(attribute)
+	if err != nil {
+		return loc, err
+	}
+
+	for splitter.HasMore() {
+		fieldName, i := splitter.Next()
+
+		if loc.index < 0 {
+			return loc, ErrNotFound
+		}
+
+		switch data[loc.start] {
+		case '{':
+			parent := parseJSONContainer(data, loc.start)
+			k, err := d.findJSONMember(parent, fieldName)
+			if err != nil {
+				return loc, err
+			}
+			if k < 0 {
+				loc = jsonLocation{parent: parent, index: -1, key: fieldName}
+				continue
+			}
+			loc = parent.location(k)
+
+		case '[':
+			if d.opts.Syntax == JSONPointer {
+				parent := parseJSONContainer(data, loc.start)
+				if fieldName == "-" {
+					loc = jsonLocation{parent: parent, index: -1}
+					continue
+				}
+				index, err := pointerIndex(fieldName)
+				if err != nil {
+					return loc, err
+				}
+				if index >= len(parent.members) {
+					return loc, ErrIndexOutOfRange
+				}
+				loc = parent.location(index)
+				break
+			}
+
+			// Ignores field if it is the first one and it is empty. This
+			// happens when using brackets on a root slice (e.g. "[1].Name").
+			if i == 0 && fieldName == "" {
+				break
+			}
+
+			parent := parseJSONContainer(data, loc.start)
+
+			if strings.HasPrefix(fieldName, "[") && strings.HasSuffix(fieldName, "]") {
+				fieldName = fieldName[1 : len(fieldName)-1]
+
+				if strings.Contains(fieldName, "=") {
+					k, err := d.filterJSONMembers(data, parent, fieldName)
+					if err != nil {
+						return loc, err
+					}
+					loc = parent.location(k)
+					break
+				}
+			}
+
+			index, err := strconv.Atoi(fieldName)
+			if err != nil {
+				return loc, ErrInvalidIndex
+			}
+			if index < 0 || index >= len(parent.members) {
+				return loc, ErrIndexOutOfRange
+			}
+			loc = parent.location(index)
+
+		default:
+			return loc, ErrNotFound
+		}
+	}
+
+	return loc, nil
+}
+
+// location returns the location of the k-th member of the container.
+func (c *jsonContainer) location(k int) jsonLocation {
+	return jsonLocation{
+		start:  c.members[k].valueStart,
+		end:    c.members[k].valueEnd,
+		parent: c,
+		index:  k,
+	}
+}
+
+// findJSONMember returns the index of the object member accessed by name,
+// according to the Dipper options, or -1 if there is no such member.
+func (d *Dipper) findJSONMember(c *jsonContainer, name string) (int, error) {
+	match, matches := -1, 0
+	for k, m := range c.members {
+		if m.key == name {
+			return k, nil
+		}
+		if d.opts.CaseInsensitive && strings.EqualFold(m.key, name) {
+			match = k
+			matches++
+		}
+	}
+	if matches > 1 {
+		return -1, ErrAmbiguousField
+	}
+	return match, nil
+}
+
+// filterJSONMembers returns the index of the first array element matching the
+// given filter expression.
+func (d *Dipper) filterJSONMembers(data []byte, c *jsonContainer, filter string) (int, error) {
+	for k, m := range c.members {
+		var elem interface{}
+		if err := json.Unmarshal(data[m.valueStart:m.valueEnd], &elem); err != nil {
+			return -1, err
+		}
+
+		_, err := d.filterSlice(reflect.ValueOf([]interface{}{elem}), filter)
+		if err == ErrFilterNotFound {
+			continue
+		}
+		if err != nil {
+			return -1, err
+		}
+		return k, nil
+	}
+	return -1, ErrFilterNotFound
+}
+
+// parseJSONContainer returns the offsets of the object or array starting at
+// the given offset of a valid JSON document.
+func parseJSONContainer(data []byte, start int) *jsonContainer {
+	c := &jsonContainer{start: start, object: data[start] == '{'}
+
+	pos := skipJSONSpace(data, start+1)
+	for data[pos] != '}' && data[pos] != ']' {
+		m := jsonMember{keyStart: pos}
+
+		if c.object {
+			m.keyEnd = scanJSONValue(data, pos)
+			_ = json.Unmarshal(data[m.keyStart:m.keyEnd], &m.key)
+			pos = skipJSONSpace(data, m.keyEnd) + 1 // Skip colon
+			pos = skipJSONSpace(data, pos)
+		} else {
+			m.keyEnd = pos
+		}
+
+		m.valueStart = pos
+		m.valueEnd = scanJSONValue(data, pos)
+		c.members = append(c.members, m)
+
+		pos = skipJSONSpace(data, m.valueEnd)
+		if data[pos] == ',' {
+			pos = skipJSONSpace(data, pos+1)
+		}
+	}
+
+	c.end = pos
+	return c
+}
+
+// scanJSONValue returns the offset right after the value starting at the
+// given offset of a valid JSON document.
+func scanJSONValue(data []byte, pos int) int {
+	depth := 0
+	for ; pos < len(data); pos++ {
+		switch data[pos] {
+		case '"':
+			pos = scanJSONString(data, pos)
+		case '{', '[':
+			depth++
+			continue
+		case '}', ']':
+			depth--
+		default:
+			if depth == 0 && !isJSONSpace(data[pos]) && data[pos] != ',' && data[pos] != ':' {
+				// Number or literal
+				for pos < len(data) && strings.IndexByte(" \t\r\n,:]}", data[pos]) < 0 {
+					pos++
+				}
+				return pos
+			}
+			continue
+		}
+		if depth == 0 {
+			return pos + 1
+		}
+	}
+	return pos
+}
+
+// scanJSONString returns the offset of the closing quote of the string
+// starting at the given offset.
+func scanJSONString(data []byte, pos int) int {
+	for pos++; pos < len(data); pos++ {
+		switch data[pos] {
+		case '\\':
+			pos++
+		case '"':
+			return pos
+		}
+	}
+	return pos
+}
+
+// skipJSONSpace returns the offset of the first non-whitespace character from
+// the given offset.
+func skipJSONSpace(data []byte, pos int) int {
+	for pos < len(data) && isJSONSpace(data[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
+// marshalJSONValue returns the JSON encoding of the given value to be written
+// in the given container of the JSON document data. If the container members
+// are written in different lines, the value is indented like them.
+func marshalJSONValue(value interface{}, data []byte, c *jsonContainer) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if c != nil && len(c.members) > 0 {
+		between := data[c.start+1 : c.members[0].keyStart]
+		if bytes.IndexByte(between, '\n') >= 0 {
+			memberIndent := lineIndent(data, c.members[0].keyStart)
+			containerIndent := lineIndent(data, c.start)
+			if strings.HasPrefix(memberIndent, containerIndent) {
+				enc.SetIndent(memberIndent, strings.TrimPrefix(memberIndent, containerIndent))
+			}
+		}
+	}
+
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// lineIndent returns the whitespace at the beginning of the line containing
+// the given offset.
+func lineIndent(data []byte, pos int) string {
+	lineStart := bytes.LastIndexByte(data[:pos], '\n') + 1
+	end := lineStart
+	for end < pos && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[lineStart:end])
+}
+
+// replaceBytes returns a copy of data with the bytes between start and end
+// replaced by the given bytes.
+func replaceBytes(data []byte, start, end int, replacement []byte) []byte {
+	result := make([]byte, 0, len(data)-(end-start)+len(replacement))
+	result = append(result, data[:start]...)
+	result = append(result, replacement...)
+	return append(result, data[end:]...)
+}
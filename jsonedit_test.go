@@ -0,0 +1,374 @@
+package dipper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+const testConfig = `{
+  "server": {
+    "host":   "localhost",
+    "port": 8080,
+    "debug": true,
+    "timeout": 1.50
+  },
+  "users": [
+    {"name": "umberto", "roles": ["admin"]},
+    {"name": "jorge", "roles": []}
+  ],
+  "empty": {},
+  "Name": 1,
+  "NAME": 2
+}
+`
+
+func TestDipper_SetJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		data      string
+		attribute string
+		value     interface{}
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "replace number",
+			data:      testConfig,
+			attribute: "server.port",
+			value:     9090,
+			want: `{
+  "server": {
+    "host":   "localhost",
+    "port": 9090,
+    "debug": true,
+    "timeout": 1.50
+  },
+  "users": [
+    {"name": "umberto", "roles": ["admin"]},
+    {"name": "jorge", "roles": []}
+  ],
+  "empty": {},
+  "Name": 1,
+  "NAME": 2
+}
+`,
+		},
+		{
+			name:      "replace with object",
+			data:      testConfig,
+			attribute: "server.debug",
+			value:     map[string]interface{}{"level": "info", "tags": []string{"a"}},
+			want: `{
+  "server": {
+    "host":   "localhost",
+    "port": 8080,
+    "debug": {
+      "level": "info",
+      "tags": [
+        "a"
+      ]
+    },
+    "timeout": 1.50
+  },
+  "users": [
+    {"name": "umberto", "roles": ["admin"]},
+    {"name": "jorge", "roles": []}
+  ],
+  "empty": {},
+  "Name": 1,
+  "NAME": 2
+}
+`,
+		},
+		{
+			name:      "add key",
+			data:      testConfig,
+			attribute: "server.tls",
+			value:     false,
+			want: `{
+  "server": {
+    "host":   "localhost",
+    "port": 8080,
+    "debug": true,
+    "timeout": 1.50,
+    "tls": false
+  },
+  "users": [
+    {"name": "umberto", "roles": ["admin"]},
+    {"name": "jorge", "roles": []}
+  ],
+  "empty": {},
+  "Name": 1,
+  "NAME": 2
+}
+`,
+		},
+		{
+			name:      "add key to inline object",
+			data:      `{"a": {"b": 1, "c": 2}}`,
+			attribute: "a.d",
+			value:     "<3>",
+			want:      `{"a": {"b": 1, "c": 2, "d": "<3>"}}`,
+		},
+		{
+			name:      "add key to single-member inline object",
+			data:      `{"c": 2}`,
+			attribute: "d",
+			value:     3,
+			want:      `{"c": 2, "d": 3}`,
+		},
+		{
+			name:      "add key to single-member compact object",
+			data:      `{"c":2}`,
+			attribute: "d",
+			value:     3,
+			want:      `{"c":2,"d":3}`,
+		},
+		{
+			name:      "add key to single-member padded object",
+			data:      `{ "c" : 2 }`,
+			attribute: "d",
+			value:     3,
+			want:      `{ "c" : 2, "d" : 3 }`,
+		},
+		{
+			name:      "append to single-element inline array",
+			opts:      dipper.Options{Syntax: dipper.JSONPointer},
+			data:      `{"a": [1]}`,
+			attribute: "/a/-",
+			value:     2,
+			want:      `{"a": [1, 2]}`,
+		},
+		{
+			name:      "add key to empty object",
+			data:      testConfig,
+			attribute: "empty.key",
+			value:     "value",
+			want: `{
+  "server": {
+    "host":   "localhost",
+    "port": 8080,
+    "debug": true,
+    "timeout": 1.50
+  },
+  "users": [
+    {"name": "umberto", "roles": ["admin"]},
+    {"name": "jorge", "roles": []}
+  ],
+  "empty": {"key":"value"},
+  "Name": 1,
+  "NAME": 2
+}
+`,
+		},
+		{
+			name:      "array element with filter",
+			data:      `{"users": [{"name": "umberto", "id": 1}, {"name": "jorge", "id": 2}]}`,
+			attribute: "users[name='jorge'].id",
+			value:     3,
+			want:      `{"users": [{"name": "umberto", "id": 1}, {"name": "jorge", "id": 3}]}`,
+		},
+		{
+			name:      "root array element",
+			data:      ` [1, 2 , 3] `,
+			attribute: "[1]",
+			value:     "two",
+			want:      ` [1, "two" , 3] `,
+		},
+		{
+			name:      "root value",
+			data:      "\n{\"a\": 1}\n",
+			attribute: "",
+			value:     []int{1},
+			want:      "\n[1]\n",
+		},
+		{
+			name:      "zero value",
+			data:      `{"a": "\"quoted\" }"}`,
+			attribute: "a",
+			value:     dipper.Zero,
+			want:      `{"a": null}`,
+		},
+		{
+			name:      "case-insensitive key",
+			opts:      dipper.Options{CaseInsensitive: true},
+			data:      `{"Server": {"Port": 1}}`,
+			attribute: "server.port",
+			value:     2,
+			want:      `{"Server": {"Port": 2}}`,
+		},
+		{
+			name:      "case-insensitive ambiguous key",
+			opts:      dipper.Options{CaseInsensitive: true},
+			data:      testConfig,
+			attribute: "name",
+			value:     3,
+			wantErr:   dipper.ErrAmbiguousField,
+		},
+		{
+			name:      "missing intermediate key",
+			data:      testConfig,
+			attribute: "client.port",
+			value:     1,
+			wantErr:   dipper.ErrNotFound,
+		},
+		{
+			name:      "index out of range",
+			data:      testConfig,
+			attribute: "users.2",
+			value:     1,
+			wantErr:   dipper.ErrIndexOutOfRange,
+		},
+		{
+			name:      "invalid index",
+			data:      testConfig,
+			attribute: "users.name",
+			value:     1,
+			wantErr:   dipper.ErrInvalidIndex,
+		},
+		{
+			name:      "field of scalar",
+			data:      testConfig,
+			attribute: "server.port.number",
+			value:     1,
+			wantErr:   dipper.ErrNotFound,
+		},
+		{
+			name:      "invalid document",
+			data:      `{"a": }`,
+			attribute: "a",
+			value:     1,
+			wantErr:   dipper.ErrInvalidJSON,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(tt.opts)
+			got, err := d.SetJSON([]byte(tt.data), tt.attribute, tt.value)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("SetJSON() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("SetJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_DeleteJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		attribute string
+		want      string
+		wantErr   error
+	}{
+		{
+			name:      "first member",
+			data:      testConfig,
+			attribute: "server.host",
+			want: `{
+  "server": {
+    "port": 8080,
+    "debug": true,
+    "timeout": 1.50
+  },
+  "users": [
+    {"name": "umberto", "roles": ["admin"]},
+    {"name": "jorge", "roles": []}
+  ],
+  "empty": {},
+  "Name": 1,
+  "NAME": 2
+}
+`,
+		},
+		{
+			name:      "last member",
+			data:      testConfig,
+			attribute: "server.timeout",
+			want: `{
+  "server": {
+    "host":   "localhost",
+    "port": 8080,
+    "debug": true
+  },
+  "users": [
+    {"name": "umberto", "roles": ["admin"]},
+    {"name": "jorge", "roles": []}
+  ],
+  "empty": {},
+  "Name": 1,
+  "NAME": 2
+}
+`,
+		},
+		{
+			name:      "only element",
+			data:      testConfig,
+			attribute: "users[name='umberto'].roles.0",
+			want: `{
+  "server": {
+    "host":   "localhost",
+    "port": 8080,
+    "debug": true,
+    "timeout": 1.50
+  },
+  "users": [
+    {"name": "umberto", "roles": []},
+    {"name": "jorge", "roles": []}
+  ],
+  "empty": {},
+  "Name": 1,
+  "NAME": 2
+}
+`,
+		},
+		{
+			name:      "middle element",
+			data:      `[1, {"a": [2]}, 3]`,
+			attribute: "[1]",
+			want:      `[1, 3]`,
+		},
+		{
+			name:      "using Delete in SetJSON",
+			data:      `{"a": 1, "b": 2}`,
+			attribute: "b",
+			want:      `{"a": 1}`,
+		},
+		{
+			name:      "key not found",
+			data:      testConfig,
+			attribute: "server.tls",
+			wantErr:   dipper.ErrNotFound,
+		},
+		{
+			name:      "root value",
+			data:      testConfig,
+			attribute: "",
+			wantErr:   dipper.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dipper.DeleteJSON([]byte(tt.data), tt.attribute)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("DeleteJSON() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("DeleteJSON() = %s, want %s", got, tt.want)
+			}
+
+			got, err = dipper.SetJSON([]byte(tt.data), tt.attribute, dipper.Delete)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("SetJSON() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("SetJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
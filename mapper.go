@@ -0,0 +1,212 @@
+package dipper
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// Mapping is a rule of a Mapper, which copies the value of an attribute of
+// the source object into an attribute of the destination object.
+type Mapping struct {
+	// From is the attribute of the source object.
+	From string
+	// To is the attribute of the destination object.
+	To string
+	// Convert is an optional function to convert the source value before it
+	// is set into the destination object.
+	Convert func(interface{}) (interface{}, error)
+}
+
+// Mapper copies values from objects of a source type into objects of a
+// destination type, following a list of mappings. It is safe for concurrent
+// use.
+type Mapper struct {
+	d        *Dipper
+	mappings []Mapping
+}
+
+// NewMapper returns a Mapper that copies values from objects of type src into
+// objects of type dst, following the given mappings in order.
+// The mappings are validated against both types, as Dipper.Validate() and
+// Dipper.ValidateSet() do. The type of a source attribute must be assignable
+// or convertible to the type of its destination attribute (e.g. numbers of
+// different types, or structs and maps with the same field names), unless the
+// mapping has a Convert function. Validation errors are returned in a
+// *PathError with the attribute that caused them.
+//
+// Example:
+//
+//	mapper, err := my_dipper.NewMapper(reflect.TypeOf(UserDTO{}), reflect.TypeOf(User{}), []dipper.Mapping{
+//	    {From: "Name", To: "Profile.DisplayName"},
+//	    {From: "Age", To: "Profile.Age"},
+//	})
+//	if err != nil {
+//	    return err
+//	}
+func (d *Dipper) NewMapper(src, dst reflect.Type, mappings []Mapping) (*Mapper, error) {
+	for _, mapping := range mappings {
+		from, err := d.resolveType(src, mapping.From, false)
+		if err != nil {
+			return nil, err
+		}
+		to, err := d.resolveType(dst, mapping.To, true)
+		if err != nil {
+			return nil, err
+		}
+		if to.t == nil {
+			continue
+		}
+		if !to.isChild && !to.addressable {
+			return nil, &PathError{Path: mapping.To, Err: ErrUnaddressable}
+		}
+		if mapping.Convert == nil && from.t != nil && !coercible(from.t, to.t) {
+			return nil, &PathError{Path: mapping.To, Err: ErrTypesDoNotMatch}
+		}
+	}
+
+	return &Mapper{d: d, mappings: append([]Mapping(nil), mappings...)}, nil
+}
+
+// Map copies the values of src into dst, which must be a pointer, following
+// the mappings of the Mapper. Values are converted to the destination types if
+// needed, as in Dipper.Merge(), and nil pointers and maps found in the
+// destination attributes are initialized. Mappings whose source attribute
+// does not reference any value (e.g. a field of a nil pointer) are skipped.
+// Errors are returned in a *PathError with the attribute that caused them, and
+// dst can be partially modified if an error occurs.
+//
+// Example:
+//
+//	var user User
+//	if err := mapper.Map(dto, &user); err != nil {
+//	    return err
+//	}
+func (m *Mapper) Map(src, dst interface{}) error {
+	if value := reflect.ValueOf(dst); value.Kind() != reflect.Ptr || value.IsNil() {
+		return ErrUnaddressable
+	}
+
+	for _, mapping := range m.mappings {
+		v := m.d.Get(src, mapping.From)
+		if err := Error(v); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			if _, ok := err.(*PathError); !ok {
+				err = &PathError{Path: mapping.From, Err: err}
+			}
+			return err
+		}
+
+		if mapping.Convert != nil {
+			var err error
+			if v, err = mapping.Convert(v); err != nil {
+				return &PathError{Path: mapping.From, Err: err}
+			}
+		}
+
+		if err := m.d.setCoerced(dst, mapping.To, v); err != nil {
+			if _, ok := err.(*PathError); !ok {
+				err = &PathError{Path: mapping.To, Err: err}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// setCoerced sets the new value to the given obj attribute as Dipper.Set()
+// does, initializing the nil pointers and maps found in the attribute and
+// converting the value to the attribute type if needed.
+func (d *Dipper) setCoerced(obj interface{}, attribute string, new interface{}) error {
+	if err := d.initParents(obj, attribute); err != nil {
+		return err
+	}
+
+	err := d.Set(obj, attribute, new)
+	if err != ErrTypesDoNotMatch || new == nil {
+		return err
+	}
+
+	value := reflect.ValueOf(obj)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	target := &setTarget{}
+	value, err = d.getReflectValue(value, attribute, target)
+	if err != nil {
+		return err
+	}
+
+	converted := reflect.New(setType(value, target)).Elem()
+	m := merger{d: d}
+	if err := m.merge(converted, reflect.ValueOf(new), ""); err != nil {
+		return err
+	}
+	return d.Set(obj, attribute, converted.Interface())
+}
+
+// initParents initializes the nil pointers and maps referenced by the parents
+// of the given obj attribute.
+func (d *Dipper) initParents(obj interface{}, attribute string) error {
+	splitter, err := d.newSplitter(attribute)
+	if err != nil {
+		return err
+	}
+
+	for splitter.HasMore() {
+		splitter.Next()
+		if !splitter.HasMore() {
+			break
+		}
+
+		parent := d.Get(obj, splitter.Parsed())
+		if Error(parent) != nil {
+			return nil
+		}
+
+		value := reflect.ValueOf(parent)
+		switch {
+		case value.Kind() == reflect.Ptr && value.IsNil():
+			err = d.Set(obj, splitter.Parsed(), reflect.New(value.Type().Elem()).Interface())
+		case value.Kind() == reflect.Map && value.IsNil():
+			err = d.Set(obj, splitter.Parsed(), reflect.MakeMap(value.Type()).Interface())
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// coercible returns true if the values of type src can be converted to the
+// type dst as Dipper.Merge() does.
+func coercible(src, dst reflect.Type) bool {
+	if src.AssignableTo(dst) {
+		return true
+	}
+	if dst.Kind() == reflect.Interface {
+		return src.Implements(dst)
+	}
+
+	src, dst = derefType(src), derefType(dst)
+	if src.AssignableTo(dst) || reflect.PtrTo(dst).Implements(jsonUnmarshalerType) {
+		return true
+	}
+
+	switch {
+	case isNumberKind(src.Kind()) && isNumberKind(dst.Kind()):
+		return true
+	case (src.Kind() == reflect.Struct || src.Kind() == reflect.Map) && (dst.Kind() == reflect.Struct || dst.Kind() == reflect.Map):
+		return true
+	case (src.Kind() == reflect.Slice || src.Kind() == reflect.Array) && dst.Kind() == reflect.Slice:
+		return coercible(src.Elem(), dst.Elem())
+	case src.Kind() == dst.Kind():
+		// Named types of the same basic kind (e.g. a string and a custom
+		// string type)
+		return src.Kind() == reflect.String || src.Kind() == reflect.Bool
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package dipper_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+type UserDTO struct {
+	Name    string
+	Age     int64
+	Email   string
+	Address *AddressDTO
+	Tags    []string
+}
+
+type AddressDTO struct {
+	Street string
+	City   string
+}
+
+type UserModel struct {
+	Profile  *Profile
+	Location Location
+	Contact  map[string]string
+	Labels   []string
+	Extra    interface{}
+}
+
+type Profile struct {
+	DisplayName string
+	Age         int
+}
+
+type Location struct {
+	Street string
+	City   string
+}
+
+func TestDipper_NewMapper(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []dipper.Mapping
+		wantErr  error
+		wantPath string
+	}{
+		{
+			name: "valid mappings",
+			mappings: []dipper.Mapping{
+				{From: "Name", To: "Profile.DisplayName"},
+				{From: "Age", To: "Profile.Age"},
+				{From: "Address", To: "Location"},
+				{From: "Email", To: "Contact.email"},
+				{From: "Tags", To: "Labels"},
+				{From: "Address.City", To: "Extra"},
+			},
+		},
+		{
+			name:     "source field not found",
+			mappings: []dipper.Mapping{{From: "Nmae", To: "Profile.DisplayName"}},
+			wantErr:  dipper.ErrNotFound,
+			wantPath: "Nmae",
+		},
+		{
+			name:     "destination field not found",
+			mappings: []dipper.Mapping{{From: "Name", To: "Profile.Name"}},
+			wantErr:  dipper.ErrNotFound,
+			wantPath: "Profile.Name",
+		},
+		{
+			name:     "types cannot be converted",
+			mappings: []dipper.Mapping{{From: "Name", To: "Profile.Age"}},
+			wantErr:  dipper.ErrTypesDoNotMatch,
+			wantPath: "Profile.Age",
+		},
+		{
+			name: "types converted by function",
+			mappings: []dipper.Mapping{{From: "Tags", To: "Profile.DisplayName", Convert: func(v interface{}) (interface{}, error) {
+				return strings.Join(v.([]string), ","), nil
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper, err := dipper.NewMapper(reflect.TypeOf(UserDTO{}), reflect.TypeOf(UserModel{}), tt.mappings)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("NewMapper() error = %v", err)
+				}
+				if mapper == nil {
+					t.Errorf("NewMapper() returned a nil Mapper")
+				}
+				return
+			}
+
+			var pathErr *dipper.PathError
+			if !errors.As(err, &pathErr) || !errors.Is(err, tt.wantErr) || pathErr.Path != tt.wantPath {
+				t.Errorf("NewMapper() error = %v, want %v at %q", err, tt.wantErr, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestMapper_Map(t *testing.T) {
+	errConvert := errors.New("conversion failed")
+
+	tests := []struct {
+		name     string
+		mappings []dipper.Mapping
+		src      interface{}
+		dst      UserModel
+		want     UserModel
+		wantErr  error
+		wantPath string
+	}{
+		{
+			name: "nested destinations are initialized",
+			mappings: []dipper.Mapping{
+				{From: "Name", To: "Profile.DisplayName"},
+				{From: "Age", To: "Profile.Age"},
+				{From: "Email", To: "Contact.email"},
+			},
+			src: UserDTO{Name: "Jane", Age: 42, Email: "jane@example.com"},
+			want: UserModel{
+				Profile: &Profile{DisplayName: "Jane", Age: 42},
+				Contact: map[string]string{"email": "jane@example.com"},
+			},
+		},
+		{
+			name: "structs and slices are converted",
+			mappings: []dipper.Mapping{
+				{From: "Address", To: "Location"},
+				{From: "Tags", To: "Labels"},
+				{From: "Address.City", To: "Extra"},
+			},
+			src: &UserDTO{Address: &AddressDTO{Street: "Main St", City: "Springfield"}, Tags: []string{"a", "b"}},
+			want: UserModel{
+				Location: Location{Street: "Main St", City: "Springfield"},
+				Labels:   []string{"a", "b"},
+				Extra:    "Springfield",
+			},
+		},
+		{
+			name: "existing values are kept",
+			mappings: []dipper.Mapping{
+				{From: "Name", To: "Profile.DisplayName"},
+				{From: "Email", To: "Contact.email"},
+			},
+			src: UserDTO{Name: "Jane", Email: "jane@example.com"},
+			dst: UserModel{Profile: &Profile{Age: 42}, Contact: map[string]string{"phone": "555"}},
+			want: UserModel{
+				Profile: &Profile{DisplayName: "Jane", Age: 42},
+				Contact: map[string]string{"phone": "555", "email": "jane@example.com"},
+			},
+		},
+		{
+			name:     "missing source values are skipped",
+			mappings: []dipper.Mapping{{From: "Address.City", To: "Location.City"}},
+			src:      UserDTO{},
+			dst:      UserModel{Location: Location{City: "Shelbyville"}},
+			want:     UserModel{Location: Location{City: "Shelbyville"}},
+		},
+		{
+			name: "convert function",
+			mappings: []dipper.Mapping{{From: "Tags", To: "Profile.DisplayName", Convert: func(v interface{}) (interface{}, error) {
+				return strings.Join(v.([]string), ","), nil
+			}}},
+			src:  UserDTO{Tags: []string{"a", "b"}},
+			want: UserModel{Profile: &Profile{DisplayName: "a,b"}},
+		},
+		{
+			name: "convert function error",
+			mappings: []dipper.Mapping{{From: "Name", To: "Profile.DisplayName", Convert: func(v interface{}) (interface{}, error) {
+				return nil, errConvert
+			}}},
+			src:      UserDTO{Name: "Jane"},
+			wantErr:  errConvert,
+			wantPath: "Name",
+		},
+		{
+			name: "converted value does not match",
+			mappings: []dipper.Mapping{{From: "Name", To: "Profile.Age", Convert: func(v interface{}) (interface{}, error) {
+				return v, nil
+			}}},
+			src:      UserDTO{Name: "Jane"},
+			wantErr:  dipper.ErrTypesDoNotMatch,
+			wantPath: "Profile.Age",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapper, err := dipper.NewMapper(reflect.TypeOf(UserDTO{}), reflect.TypeOf(UserModel{}), tt.mappings)
+			if err != nil {
+				t.Fatalf("NewMapper() error = %v", err)
+			}
+
+			dst := tt.dst
+			err = mapper.Map(tt.src, &dst)
+			if tt.wantErr != nil {
+				var pathErr *dipper.PathError
+				if !errors.As(err, &pathErr) || !errors.Is(err, tt.wantErr) || pathErr.Path != tt.wantPath {
+					t.Errorf("Map() error = %v, want %v at %q", err, tt.wantErr, tt.wantPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Map() error = %v", err)
+			}
+			if !reflect.DeepEqual(dst, tt.want) {
+				t.Errorf("Map() = %#v, want %#v", dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapper_Map_NotPointer(t *testing.T) {
+	mapper, err := dipper.NewMapper(reflect.TypeOf(UserDTO{}), reflect.TypeOf(UserModel{}), nil)
+	if err != nil {
+		t.Fatalf("NewMapper() error = %v", err)
+	}
+	if err := mapper.Map(UserDTO{}, UserModel{}); err != dipper.ErrUnaddressable {
+		t.Errorf("Map() error = %v, want %v", err, dipper.ErrUnaddressable)
+	}
+}
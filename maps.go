@@ -0,0 +1,131 @@
+package dipper
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// findMapKey returns the key of the given map value accessed by the given
+// name, according to the Dipper options. It also returns true if the key
+// exists in the map.
+// If the key does not exist, the returned key can be used to add a new
+// element to the map.
+func (d *Dipper) findMapKey(m reflect.Value, name string) (reflect.Value, bool, error) {
+	keyType := m.Type().Key()
+
+	var keys []reflect.Value
+	if keyType.Kind() == reflect.Interface {
+		for _, key := range interfaceMapKeys(name) {
+			if key.Type().AssignableTo(keyType) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			return reflect.Value{}, false, ErrMapKeyNotString
+		}
+	} else {
+		key, err := mapKey(keyType, name)
+		if err != nil {
+			return reflect.Value{}, false, err
+		}
+		keys = []reflect.Value{key}
+	}
+
+	for _, key := range keys {
+		if m.MapIndex(key).IsValid() {
+			return key, true, nil
+		}
+	}
+
+	if !d.opts.CaseInsensitive {
+		return keys[0], false, nil
+	}
+
+	var match reflect.Value
+	for _, k := range m.MapKeys() {
+		ks := getElemSafe(k)
+		if ks.Kind() != reflect.String || !strings.EqualFold(ks.String(), name) {
+			continue
+		}
+		if match.IsValid() {
+			return reflect.Value{}, false, ErrAmbiguousField
+		}
+		match = k
+	}
+
+	if match.IsValid() {
+		return match, true, nil
+	}
+	return keys[0], false, nil
+}
+
+// mapKey converts the given name to a map key of the given type.
+// Types implementing encoding.TextUnmarshaler are parsed with UnmarshalText.
+// Otherwise, string, integer and boolean kinds are supported.
+func mapKey(keyType reflect.Type, name string) (reflect.Value, error) {
+	if reflect.PtrTo(keyType).Implements(textUnmarshalerType) {
+		key := reflect.New(keyType)
+		if err := key.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(name)); err != nil {
+			return reflect.Value{}, ErrInvalidMapKey
+		}
+		return key.Elem(), nil
+	}
+
+	key := reflect.New(keyType).Elem()
+
+	switch keyType.Kind() {
+	case reflect.String:
+		key.SetString(name)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, ErrInvalidMapKey
+		}
+		key.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(name, 10, keyType.Bits())
+		if err != nil {
+			return reflect.Value{}, ErrInvalidMapKey
+		}
+		key.SetUint(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(name)
+		if err != nil {
+			return reflect.Value{}, ErrInvalidMapKey
+		}
+		key.SetBool(b)
+	default:
+		return reflect.Value{}, ErrMapKeyNotString
+	}
+
+	return key, nil
+}
+
+// interfaceMapKeys returns the possible keys of an interface map accessed by
+// the given name, in order of preference: the name itself, followed by its
+// integer, float and boolean forms (if the name can be parsed as such).
+func interfaceMapKeys(name string) []reflect.Value {
+	keys := []reflect.Value{reflect.ValueOf(name)}
+
+	if n, err := strconv.ParseInt(name, 10, 64); err == nil {
+		if int64(int(n)) == n {
+			keys = append(keys, reflect.ValueOf(int(n)))
+		}
+		keys = append(keys, reflect.ValueOf(n))
+	}
+	if n, err := strconv.ParseUint(name, 10, 64); err == nil {
+		keys = append(keys, reflect.ValueOf(n))
+	}
+	if f, err := strconv.ParseFloat(name, 64); err == nil {
+		keys = append(keys, reflect.ValueOf(f))
+	}
+	if name == "true" || name == "false" {
+		keys = append(keys, reflect.ValueOf(name == "true"))
+	}
+
+	return keys
+}
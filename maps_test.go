@@ -0,0 +1,316 @@
+package dipper_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+type UserID struct {
+	Region string
+	Number int
+}
+
+func (id UserID) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%s-%d", id.Region, id.Number)), nil
+}
+
+func (id *UserID) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(strings.Replace(string(text), "-", " ", 1), "%s %d", &id.Region, &id.Number)
+	return err
+}
+
+func TestDipper_GetMapKeyTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "int key",
+			obj:       map[int]string{1: "one", -2: "minus two"},
+			attribute: "-2",
+			want:      "minus two",
+		},
+		{
+			name:      "int8 key out of range",
+			obj:       map[int8]string{1: "one"},
+			attribute: "1000",
+			want:      dipper.ErrInvalidMapKey,
+		},
+		{
+			name:      "uint64 key",
+			obj:       map[uint64]string{18446744073709551615: "max"},
+			attribute: "18446744073709551615",
+			want:      "max",
+		},
+		{
+			name:      "negative uint key",
+			obj:       map[uint]string{1: "one"},
+			attribute: "-1",
+			want:      dipper.ErrInvalidMapKey,
+		},
+		{
+			name:      "bool key",
+			obj:       map[bool]string{true: "yes", false: "no"},
+			attribute: "false",
+			want:      "no",
+		},
+		{
+			name:      "text unmarshaler key",
+			obj:       map[UserID]string{{Region: "eu", Number: 7}: "Umberto"},
+			attribute: "eu-7",
+			want:      "Umberto",
+		},
+		{
+			name:      "invalid text unmarshaler key",
+			obj:       map[UserID]string{{Region: "eu", Number: 7}: "Umberto"},
+			attribute: "eu",
+			want:      dipper.ErrInvalidMapKey,
+		},
+		{
+			name:      "int key not found",
+			obj:       map[int]string{1: "one"},
+			attribute: "2",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "nested int key",
+			obj:       map[string]map[int][]string{"ids": {10: {"a", "b"}}},
+			attribute: "ids/10/1",
+			want:      "b",
+		},
+		{
+			name:      "interface map with string key",
+			obj:       map[interface{}]interface{}{"1": "string", 1: "int"},
+			attribute: "1",
+			want:      "string",
+		},
+		{
+			name:      "interface map with int key",
+			obj:       map[interface{}]interface{}{1: "int"},
+			attribute: "1",
+			want:      "int",
+		},
+		{
+			name:      "interface map with float key",
+			obj:       map[interface{}]interface{}{1.5: "float"},
+			attribute: "1.5",
+			want:      "float",
+		},
+		{
+			name:      "interface map with bool key",
+			obj:       map[interface{}]interface{}{true: "bool"},
+			attribute: "true",
+			want:      "bool",
+		},
+		{
+			name:      "unsupported key type",
+			obj:       map[float32]string{1: "one"},
+			attribute: "1",
+			want:      dipper.ErrMapKeyNotString,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{Separator: "/"})
+			got := d.Get(tt.obj, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_SetMapKeyTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       interface{}
+		attribute string
+		newValue  interface{}
+		wantErr   error
+		want      interface{}
+	}{
+		{
+			name:      "update int key",
+			obj:       map[int]string{1: "one"},
+			attribute: "1",
+			newValue:  "uno",
+			want:      map[int]string{1: "uno"},
+		},
+		{
+			name:      "add uint key",
+			obj:       map[uint16]string{1: "one"},
+			attribute: "2",
+			newValue:  "two",
+			want:      map[uint16]string{1: "one", 2: "two"},
+		},
+		{
+			name:      "delete bool key",
+			obj:       map[bool]string{true: "yes", false: "no"},
+			attribute: "true",
+			newValue:  dipper.Delete,
+			want:      map[bool]string{false: "no"},
+		},
+		{
+			name:      "add text unmarshaler key",
+			obj:       map[UserID]string{},
+			attribute: "us-3",
+			newValue:  "Ursula",
+			want:      map[UserID]string{{Region: "us", Number: 3}: "Ursula"},
+		},
+		{
+			name:      "update interface map with int key",
+			obj:       map[interface{}]interface{}{1: "one"},
+			attribute: "1",
+			newValue:  "uno",
+			want:      map[interface{}]interface{}{1: "uno"},
+		},
+		{
+			name:      "add interface map key",
+			obj:       map[interface{}]interface{}{1: "one"},
+			attribute: "2",
+			newValue:  "two",
+			want:      map[interface{}]interface{}{1: "one", "2": "two"},
+		},
+		{
+			name:      "invalid int key",
+			obj:       map[int]string{1: "one"},
+			attribute: "one",
+			newValue:  "uno",
+			wantErr:   dipper.ErrInvalidMapKey,
+			want:      map[int]string{1: "one"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dipper.Set(tt.obj, tt.attribute, tt.newValue)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("Set() = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.obj, tt.want) {
+				t.Errorf("Set() => map = %v, want %v", tt.obj, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_GetMapCaseInsensitive(t *testing.T) {
+	type StringKey string
+
+	tests := []struct {
+		name      string
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "map key",
+			obj:       toJSONMap(getTestStruct()),
+			attribute: "AUTHOR.Name",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "map key in filter",
+			obj:       toJSONMap(getTestStruct()),
+			attribute: "genres[NAME='Crime'].ID",
+			want:      1.0,
+		},
+		{
+			name:      "interface map key",
+			obj:       map[interface{}]interface{}{1: "one", "Two": 2},
+			attribute: "two",
+			want:      2,
+		},
+		{
+			name:      "named string map key",
+			obj:       map[StringKey]int{"Foo": 1},
+			attribute: "foo",
+			want:      1,
+		},
+		{
+			name:      "exact match takes precedence",
+			obj:       map[string]int{"Name": 1, "name": 2},
+			attribute: "name",
+			want:      2,
+		},
+		{
+			name:      "ambiguous key",
+			obj:       map[string]int{"Name": 1, "name": 2},
+			attribute: "NAME",
+			want:      dipper.ErrAmbiguousField,
+		},
+		{
+			name:      "not found",
+			obj:       map[string]int{"Name": 1},
+			attribute: "Names",
+			want:      dipper.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{CaseInsensitive: true})
+			got := d.Get(tt.obj, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_SetMapCaseInsensitive(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       map[string]interface{}
+		attribute string
+		newValue  interface{}
+		wantErr   error
+		want      map[string]interface{}
+	}{
+		{
+			name:      "update existing key",
+			obj:       map[string]interface{}{"Name": "foo"},
+			attribute: "NAME",
+			newValue:  "bar",
+			want:      map[string]interface{}{"Name": "bar"},
+		},
+		{
+			name:      "add new key",
+			obj:       map[string]interface{}{"Name": "foo"},
+			attribute: "Title",
+			newValue:  "bar",
+			want:      map[string]interface{}{"Name": "foo", "Title": "bar"},
+		},
+		{
+			name:      "delete existing key",
+			obj:       map[string]interface{}{"Name": "foo", "Title": "bar"},
+			attribute: "title",
+			newValue:  dipper.Delete,
+			want:      map[string]interface{}{"Name": "foo"},
+		},
+		{
+			name:      "ambiguous key",
+			obj:       map[string]interface{}{"Name": "foo", "name": "bar"},
+			attribute: "NAME",
+			newValue:  "baz",
+			wantErr:   dipper.ErrAmbiguousField,
+			want:      map[string]interface{}{"Name": "foo", "name": "bar"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{CaseInsensitive: true})
+			err := d.Set(tt.obj, tt.attribute, tt.newValue)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Errorf("Set() = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.obj, tt.want) {
+				t.Errorf("Set() => map = %v, want %v", tt.obj, tt.want)
+			}
+		})
+	}
+}
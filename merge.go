@@ -0,0 +1,429 @@
+package dipper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// SliceStrategy defines how slices are merged by Dipper.Merge().
+type SliceStrategy int
+
+const (
+	// SliceReplace replaces the destination slice with the source slice.
+	SliceReplace SliceStrategy = iota
+	// SliceAppend appends the source slice elements to the destination slice.
+	SliceAppend
+	// SliceMergeByKey merges the source slice elements into the destination
+	// elements with the same key (see MergeOptions.SliceKey). Elements
+	// without a matching key are appended.
+	SliceMergeByKey
+)
+
+// MergeOptions defines the behavior of Dipper.Merge().
+type MergeOptions struct {
+	// Slices is the strategy used to merge slices. The default strategy is
+	// SliceReplace.
+	Slices SliceStrategy
+	// SliceKey is the attribute of the slice elements used to match them with
+	// the SliceMergeByKey strategy (e.g. "ID" or "Author.Name").
+	SliceKey string
+	// NilDeletes makes nil values in the source delete the corresponding map
+	// keys, and zero the corresponding struct fields, as JSON Merge Patch
+	// does with null values. By default, nil values are ignored.
+	NilDeletes bool
+	// SkipZero ignores the source values that are the zero value of their
+	// type (e.g. 0 or ""), so they do not overwrite the destination values.
+	SkipZero bool
+}
+
+// Merge deep-merges src into dst, which must be addressable. Maps and structs
+// are merged recursively: map keys and struct fields of src are merged into
+// the ones of dst with the same name, so values only present in dst are kept.
+// Other values (and values of different kinds) are copied from src, replacing
+// the dst values. Maps and structs can be merged into each other, using the
+// same field names as attributes (e.g. according to the TagName option), and
+// values are converted to the dst types if needed, as in Dipper.ApplyPatch().
+// A struct field of src with no matching field in dst returns ErrNotFound.
+// Unlike Dipper.ApplyPatch(), dst can be partially modified if an error
+// occurs.
+//
+// Example:
+//
+//	config := defaultConfig()
+//	if err := my_dipper.Merge(&config, envConfig, dipper.MergeOptions{SkipZero: true}); err != nil {
+//	    return err
+//	}
+func (d *Dipper) Merge(dst, src interface{}, opts MergeOptions) error {
+	value := reflect.ValueOf(dst)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ErrUnaddressable
+		}
+		value = value.Elem()
+	}
+	if !value.CanSet() && (value.Kind() != reflect.Map || value.IsNil()) {
+		return ErrUnaddressable
+	}
+
+	m := merger{d: d, opts: opts}
+	return m.merge(value, reflect.ValueOf(src), "")
+}
+
+// MergePatch applies the JSON Merge Patch (RFC 7386) document patch to dst,
+// which must be addressable. It works as Dipper.Merge() with the NilDeletes
+// option, so null values delete map keys and zero struct fields, and arrays
+// are replaced.
+//
+// Example:
+//
+//	if err := my_dipper.MergePatch(&book, body); err != nil {
+//	    return err
+//	}
+func (d *Dipper) MergePatch(dst interface{}, patch []byte) error {
+	var src interface{}
+	if err := json.Unmarshal(patch, &src); err != nil {
+		return ErrInvalidJSON
+	}
+	return d.Merge(dst, src, MergeOptions{NilDeletes: true})
+}
+
+// merger merges values using the given options.
+type merger struct {
+	d    *Dipper
+	opts MergeOptions
+	// parseStrings allows to parse string values as JSON when they cannot be
+	// converted to the dst type (e.g. "8080" to an int)
+	parseStrings bool
+	// ignoreUnknown ignores the src entries with no matching dst struct field
+	ignoreUnknown bool
+	// reportPaths returns the errors in a *PathError with the attribute of the
+	// value that caused them
+	reportPaths bool
+}
+
+// merge merges src into dst, which must be settable or a non-nil map.
+// path is the attribute of dst, used to report errors.
+func (m *merger) merge(dst, src reflect.Value, path string) error {
+	return m.fail(m.mergeValue(dst, src, path), path)
+}
+
+// fail returns the given error in a *PathError with the given path if the
+// reportPaths option is enabled and it is not one already.
+func (m *merger) fail(err error, path string) error {
+	if err == nil || !m.reportPaths {
+		return err
+	}
+	if _, ok := err.(*PathError); ok {
+		return err
+	}
+	return &PathError{Path: path, Err: err}
+}
+
+// mergeValue merges src into dst, as merge does.
+func (m *merger) mergeValue(dst, src reflect.Value, path string) error {
+	src = getElemSafe(src)
+	if !src.IsValid() {
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return m.merge(dst.Elem(), src, path)
+
+	case reflect.Interface:
+		// The dynamic value is merged into a settable copy. If it cannot be
+		// merged with src, it is replaced by a copy of src (or a new map
+		// with its keys, so NilDeletes also applies to them)
+		current := dst.Elem()
+		if !current.IsValid() || !mergeable(current, src) {
+			if src.Kind() != reflect.Map {
+				return m.setConverted(dst, src)
+			}
+			current = reflect.Zero(src.Type())
+		}
+		if !current.Type().AssignableTo(dst.Type()) {
+			return ErrTypesDoNotMatch
+		}
+		c := reflect.New(current.Type()).Elem()
+		c.Set(current)
+		if err := m.merge(c, src, path); err != nil {
+			return err
+		}
+		dst.Set(c)
+		return nil
+
+	case reflect.Map:
+		if mergeable(dst, src) {
+			return m.mergeMap(dst, src, path)
+		}
+
+	case reflect.Struct:
+		if mergeable(dst, src) {
+			return m.mergeStruct(dst, src, path)
+		}
+
+	case reflect.Slice:
+		if src.Kind() == reflect.Slice || src.Kind() == reflect.Array {
+			return m.mergeSlice(dst, src, path)
+		}
+	}
+
+	return m.setConverted(dst, src)
+}
+
+// mergeable returns true if src can be merged into dst, instead of replacing
+// it.
+func mergeable(dst, src reflect.Value) bool {
+	switch dst.Kind() {
+	case reflect.Map:
+	case reflect.Struct:
+		if !hasExportedFields(dst.Type()) {
+			return false
+		}
+	case reflect.Slice:
+		return src.Kind() == reflect.Slice || src.Kind() == reflect.Array
+	default:
+		return false
+	}
+
+	switch src.Kind() {
+	case reflect.Map:
+		return true
+	case reflect.Struct:
+		return hasExportedFields(src.Type())
+	}
+	return false
+}
+
+// mergeMap merges the keys of the src map, or the fields of the src struct,
+// into the dst map.
+func (m *merger) mergeMap(dst, src reflect.Value, path string) error {
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMap(dst.Type()))
+	}
+
+	for _, entry := range m.entries(src) {
+		entryPath := m.d.joinAttribute(path, entry.name)
+
+		var key reflect.Value
+		if entry.key.IsValid() && entry.key.Type().AssignableTo(dst.Type().Key()) {
+			key = entry.key
+		} else {
+			var err error
+			if key, _, err = m.d.findMapKey(dst, entry.name); err != nil {
+				return m.fail(err, entryPath)
+			}
+		}
+
+		if isNil(entry.value) {
+			if m.opts.NilDeletes {
+				dst.SetMapIndex(key, reflect.Value{})
+			}
+			continue
+		}
+		if m.skip(entry.value) {
+			continue
+		}
+
+		// Map values are not settable, so they are merged into a copy
+		elem := reflect.New(dst.Type().Elem()).Elem()
+		if current := dst.MapIndex(key); current.IsValid() {
+			elem.Set(current)
+		}
+		if err := m.merge(elem, entry.value, entryPath); err != nil {
+			return err
+		}
+		dst.SetMapIndex(key, elem)
+	}
+	return nil
+}
+
+// mergeStruct merges the keys of the src map, or the fields of the src
+// struct, into the dst struct.
+func (m *merger) mergeStruct(dst, src reflect.Value, path string) error {
+	for _, entry := range m.entries(src) {
+		entryPath := m.d.joinAttribute(path, entry.name)
+
+		if isNil(entry.value) && !m.opts.NilDeletes {
+			continue
+		}
+		if !isNil(entry.value) && m.skip(entry.value) {
+			continue
+		}
+
+		field, err := m.d.getStructField(dst, entry.name, true)
+		if err == ErrNotFound && m.ignoreUnknown {
+			continue
+		}
+		if err != nil {
+			return m.fail(err, entryPath)
+		}
+		if !field.CanSet() {
+			return m.fail(ErrUnaddressable, entryPath)
+		}
+
+		if isNil(entry.value) {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		if err := m.merge(field, entry.value, entryPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeSlice merges the src slice into the dst slice, according to the slice
+// strategy.
+func (m *merger) mergeSlice(dst, src reflect.Value, path string) error {
+	elemType := dst.Type().Elem()
+
+	switch m.opts.Slices {
+	case SliceAppend:
+		result := dst
+		for i := 0; i < src.Len(); i++ {
+			elem := reflect.New(elemType).Elem()
+			if err := m.merge(elem, src.Index(i), m.d.joinAttribute(path, strconv.Itoa(result.Len()))); err != nil {
+				return err
+			}
+			result = reflect.Append(result, elem)
+		}
+		dst.Set(result)
+		return nil
+
+	case SliceMergeByKey:
+		result := reflect.MakeSlice(dst.Type(), dst.Len(), dst.Len())
+		reflect.Copy(result, dst)
+
+		for i := 0; i < src.Len(); i++ {
+			srcElem := src.Index(i)
+			j := m.findByKey(result, srcElem)
+			if j < 0 {
+				result = reflect.Append(result, reflect.Zero(elemType))
+				j = result.Len() - 1
+			}
+			if err := m.merge(result.Index(j), srcElem, m.d.joinAttribute(path, strconv.Itoa(j))); err != nil {
+				return err
+			}
+		}
+		dst.Set(result)
+		return nil
+	}
+
+	result := reflect.MakeSlice(dst.Type(), src.Len(), src.Len())
+	for i := 0; i < src.Len(); i++ {
+		if err := m.merge(result.Index(i), src.Index(i), m.d.joinAttribute(path, strconv.Itoa(i))); err != nil {
+			return err
+		}
+	}
+	dst.Set(result)
+	return nil
+}
+
+// findByKey returns the index of the element of the slice with the same key
+// as the given element, or -1 if there is no such element.
+func (m *merger) findByKey(slice, elem reflect.Value) int {
+	key := m.d.Get(elem.Interface(), m.opts.SliceKey)
+	if Error(key) != nil {
+		return -1
+	}
+
+	for i := 0; i < slice.Len(); i++ {
+		other := m.d.Get(slice.Index(i).Interface(), m.opts.SliceKey)
+		if Error(other) == nil && jsonEqual(key, other) {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeEntry is a map key or struct field of a value to be merged.
+type mergeEntry struct {
+	name  string
+	key   reflect.Value // the map key, or an invalid value for struct fields
+	value reflect.Value
+}
+
+// entries returns the map keys or the struct fields of the given value, in a
+// deterministic order.
+func (m *merger) entries(value reflect.Value) []mergeEntry {
+	var entries []mergeEntry
+
+	if value.Kind() == reflect.Struct {
+		for _, f := range m.d.structFieldValues(value) {
+			entries = append(entries, mergeEntry{name: f.name, value: f.value})
+		}
+		return entries
+	}
+
+	for _, key := range sortedMapKeys(value) {
+		entries = append(entries, mergeEntry{
+			name:  fmt.Sprint(key.Interface()),
+			key:   key,
+			value: value.MapIndex(key),
+		})
+	}
+	return entries
+}
+
+// skip returns true if the given value must not be merged because of the
+// SkipZero option.
+func (m *merger) skip(value reflect.Value) bool {
+	value = getElemSafe(value)
+	return m.opts.SkipZero && value.IsValid() && value.IsZero()
+}
+
+// isNil returns true if the given value is invalid or a nil pointer,
+// interface, map or slice.
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	}
+	return false
+}
+
+// setConverted sets a copy of src to dst, converting it to the dst type if
+// needed.
+func (m *merger) setConverted(dst, src reflect.Value) error {
+	if !dst.CanSet() {
+		return ErrUnaddressable
+	}
+
+	c := copyValue(src)
+	if c.Type().AssignableTo(dst.Type()) {
+		dst.Set(c)
+		return nil
+	}
+
+	converted, err := convertValue(c.Interface(), dst.Type())
+	if err != nil && m.parseStrings && c.Kind() == reflect.String {
+		parsed := reflect.New(dst.Type())
+		if json.Unmarshal([]byte(c.String()), parsed.Interface()) == nil {
+			converted, err = parsed.Elem().Interface(), nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	dst.Set(valueOf(converted, dst.Type()))
+	return nil
+}
+
+// sortedMapKeys returns the keys of the given map sorted by their string
+// representation.
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
@@ -0,0 +1,254 @@
+package dipper_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/flusflas/dipper"
+)
+
+type ServerConfig struct {
+	Host    string            `json:"host"`
+	Port    int               `json:"port"`
+	Timeout time.Duration     `json:"timeout"`
+	TLS     *TLSConfig        `json:"tls"`
+	Labels  map[string]string `json:"labels"`
+	Routes  []Route           `json:"routes"`
+}
+
+type TLSConfig struct {
+	Cert string `json:"cert"`
+	Key  string `json:"key"`
+}
+
+type Route struct {
+	Path    string `json:"path"`
+	Backend string `json:"backend"`
+}
+
+func TestDipper_Merge(t *testing.T) {
+	defaults := func() *ServerConfig {
+		return &ServerConfig{
+			Host:    "localhost",
+			Port:    8080,
+			Timeout: time.Second,
+			Labels:  map[string]string{"env": "dev", "team": "core"},
+			Routes:  []Route{{Path: "/", Backend: "web"}, {Path: "/api", Backend: "api"}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		opts    dipper.Options
+		dst     interface{}
+		src     interface{}
+		mopts   dipper.MergeOptions
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name:  "struct into struct",
+			dst:   defaults(),
+			src:   &ServerConfig{Port: 9090, TLS: &TLSConfig{Cert: "cert.pem"}, Labels: map[string]string{"env": "prod"}},
+			mopts: dipper.MergeOptions{SkipZero: true},
+			want: &ServerConfig{
+				Host:    "localhost",
+				Port:    9090,
+				Timeout: time.Second,
+				TLS:     &TLSConfig{Cert: "cert.pem"},
+				Labels:  map[string]string{"env": "prod", "team": "core"},
+				Routes:  []Route{{Path: "/", Backend: "web"}, {Path: "/api", Backend: "api"}},
+			},
+		},
+		{
+			name: "zero values overwrite by default",
+			dst:  &TLSConfig{Cert: "cert.pem", Key: "key.pem"},
+			src:  TLSConfig{Cert: "other.pem"},
+			want: &TLSConfig{Cert: "other.pem"},
+		},
+		{
+			name: "map into struct using tags",
+			opts: dipper.Options{TagName: "json"},
+			dst:  defaults(),
+			src: map[string]interface{}{
+				"port":   9090.0,
+				"tls":    map[string]interface{}{"key": "key.pem"},
+				"labels": map[string]interface{}{"team": "edge"},
+				"routes": []interface{}{map[string]interface{}{"path": "/v2", "backend": "api2"}},
+			},
+			want: &ServerConfig{
+				Host:    "localhost",
+				Port:    9090,
+				Timeout: time.Second,
+				TLS:     &TLSConfig{Key: "key.pem"},
+				Labels:  map[string]string{"env": "dev", "team": "edge"},
+				Routes:  []Route{{Path: "/v2", Backend: "api2"}},
+			},
+		},
+		{
+			name:  "append slices",
+			dst:   defaults(),
+			src:   &ServerConfig{Routes: []Route{{Path: "/admin", Backend: "admin"}}},
+			mopts: dipper.MergeOptions{Slices: dipper.SliceAppend, SkipZero: true},
+			want: &ServerConfig{
+				Host:    "localhost",
+				Port:    8080,
+				Timeout: time.Second,
+				Labels:  map[string]string{"env": "dev", "team": "core"},
+				Routes:  []Route{{Path: "/", Backend: "web"}, {Path: "/api", Backend: "api"}, {Path: "/admin", Backend: "admin"}},
+			},
+		},
+		{
+			name: "merge slices by key",
+			dst:  defaults(),
+			src: &ServerConfig{Routes: []Route{
+				{Path: "/api", Backend: "api2"},
+				{Path: "/admin", Backend: "admin"},
+			}},
+			mopts: dipper.MergeOptions{Slices: dipper.SliceMergeByKey, SliceKey: "Path", SkipZero: true},
+			want: &ServerConfig{
+				Host:    "localhost",
+				Port:    8080,
+				Timeout: time.Second,
+				Labels:  map[string]string{"env": "dev", "team": "core"},
+				Routes:  []Route{{Path: "/", Backend: "web"}, {Path: "/api", Backend: "api2"}, {Path: "/admin", Backend: "admin"}},
+			},
+		},
+		{
+			name: "nil values are ignored",
+			dst:  &map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2}},
+			src:  map[string]interface{}{"a": nil, "b": map[string]interface{}{"c": nil, "d": 3}},
+			want: &map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2, "d": 3}},
+		},
+		{
+			name:  "nil values delete",
+			dst:   &map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2}},
+			src:   map[string]interface{}{"a": nil, "b": map[string]interface{}{"c": nil, "d": 3}, "e": map[string]interface{}{"f": nil}},
+			mopts: dipper.MergeOptions{NilDeletes: true},
+			want:  &map[string]interface{}{"b": map[string]interface{}{"d": 3}, "e": map[string]interface{}{}},
+		},
+		{
+			name:  "nil values zero struct fields",
+			dst:   &ServerConfig{Host: "localhost", TLS: &TLSConfig{}},
+			src:   map[string]interface{}{"TLS": nil},
+			mopts: dipper.MergeOptions{NilDeletes: true},
+			want:  &ServerConfig{Host: "localhost"},
+		},
+		{
+			name:  "nil values zero struct fields when skipping zero values",
+			dst:   &ServerConfig{Host: "localhost", Labels: map[string]string{"env": "dev"}},
+			src:   &ServerConfig{Port: 9090},
+			mopts: dipper.MergeOptions{NilDeletes: true, SkipZero: true},
+			want:  &ServerConfig{Host: "localhost", Port: 9090},
+		},
+		{
+			name: "struct into map",
+			dst:  &map[string]interface{}{"Cert": "a", "Other": true},
+			src:  TLSConfig{Cert: "b", Key: "c"},
+			want: &map[string]interface{}{"Cert": "b", "Key": "c", "Other": true},
+		},
+		{
+			name: "different kinds are replaced",
+			dst:  &map[string]interface{}{"a": []interface{}{1}, "b": "text"},
+			src:  map[string]interface{}{"a": map[string]interface{}{"x": 1}, "b": []interface{}{2}},
+			want: &map[string]interface{}{"a": map[string]interface{}{"x": 1}, "b": []interface{}{2}},
+		},
+		{
+			name: "map without pointer",
+			dst:  map[string]int{"a": 1},
+			src:  map[string]int{"b": 2},
+			want: map[string]int{"a": 1, "b": 2},
+		},
+		{
+			name:    "unknown field",
+			dst:     &TLSConfig{},
+			src:     map[string]interface{}{"Password": "secret"},
+			want:    &TLSConfig{},
+			wantErr: dipper.ErrNotFound,
+		},
+		{
+			name:    "types do not match",
+			dst:     &TLSConfig{},
+			src:     map[string]interface{}{"Cert": 1},
+			want:    &TLSConfig{},
+			wantErr: dipper.ErrTypesDoNotMatch,
+		},
+		{
+			name:    "unaddressable",
+			dst:     TLSConfig{},
+			src:     TLSConfig{Cert: "a"},
+			want:    TLSConfig{},
+			wantErr: dipper.ErrUnaddressable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dipper.New(tt.opts).Merge(tt.dst, tt.src, tt.mopts)
+			if err != tt.wantErr {
+				t.Fatalf("Merge() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.dst, tt.want) {
+				t.Errorf("Merge() dst = %#v, want %#v", tt.dst, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_Merge_DoesNotShareValues(t *testing.T) {
+	src := map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{1}}}
+	dst := map[string]interface{}{}
+
+	if err := dipper.Merge(dst, src, dipper.MergeOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dipper.Set(dst, "a.b.0", 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := dipper.Get(src, "a.b.0"); got != 1 {
+		t.Errorf("Merge() shares values with src, got %v", got)
+	}
+}
+
+func TestDipper_MergePatch(t *testing.T) {
+	// Example from RFC 7386
+	target := map[string]interface{}{
+		"title":   "Goodbye!",
+		"author":  map[string]interface{}{"givenName": "John", "familyName": "Doe"},
+		"tags":    []interface{}{"example", "sample"},
+		"content": "This will be unchanged",
+	}
+	patch := `{
+		"title": "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author": {"familyName": null},
+		"tags": ["example"]
+	}`
+	want := map[string]interface{}{
+		"title":       "Hello!",
+		"author":      map[string]interface{}{"givenName": "John"},
+		"tags":        []interface{}{"example"},
+		"content":     "This will be unchanged",
+		"phoneNumber": "+01-123-456-7890",
+	}
+
+	if err := dipper.MergePatch(&target, []byte(patch)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(target, want) {
+		t.Errorf("MergePatch() = %v, want %v", target, want)
+	}
+
+	d := dipper.New(dipper.Options{TagName: "json"})
+	config := &ServerConfig{Host: "localhost", TLS: &TLSConfig{Cert: "cert.pem"}}
+	if err := d.MergePatch(config, []byte(`{"port": 443, "tls": null}`)); err != nil {
+		t.Fatal(err)
+	}
+	if wantConfig := (&ServerConfig{Host: "localhost", Port: 443}); !reflect.DeepEqual(config, wantConfig) {
+		t.Errorf("MergePatch() = %v, want %v", config, wantConfig)
+	}
+
+	if err := d.MergePatch(config, []byte(`{"port": `)); err != dipper.ErrInvalidJSON {
+		t.Errorf("MergePatch() error = %v, want %v", err, dipper.ErrInvalidJSON)
+	}
+}
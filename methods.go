@@ -0,0 +1,84 @@
+package dipper
+
+import (
+	"reflect"
+	"strings"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// isMethodCall returns true if the given field name is a method call (e.g.
+// "FullName()").
+func isMethodCall(fieldName string) bool {
+	return len(fieldName) > 2 && strings.HasSuffix(fieldName, "()")
+}
+
+// findMethod returns the exported method of the given value with the given
+// name, looking for methods with both value and pointer receivers. If the
+// method has a pointer receiver and the value is not addressable, the method
+// is bound to a copy of the value.
+// It returns an invalid reflect.Value if the method does not exist.
+func findMethod(value reflect.Value, name string) reflect.Value {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		if method := value.MethodByName(name); method.IsValid() {
+			return method
+		}
+		value = value.Elem()
+	}
+
+	if !value.IsValid() {
+		return reflect.Value{}
+	}
+
+	if method := value.MethodByName(name); method.IsValid() {
+		return method
+	}
+
+	if _, ok := reflect.PtrTo(value.Type()).MethodByName(name); !ok {
+		return reflect.Value{}
+	}
+	if !value.CanAddr() {
+		addressable := reflect.New(value.Type())
+		addressable.Elem().Set(value)
+		return addressable.MethodByName(name)
+	}
+	return value.Addr().MethodByName(name)
+}
+
+// hasMethod returns true if the given value has the method referenced by
+// fieldName (e.g. "FullName()").
+func hasMethod(value reflect.Value, fieldName string) bool {
+	return findMethod(value, strings.TrimSuffix(fieldName, "()")).IsValid()
+}
+
+// callMethod calls the method of the given value referenced by fieldName
+// (e.g. "FullName()") and returns its result. It also returns false if the
+// value has no such method.
+// The method must take no arguments and return a single value or a value and
+// an error. If the method returns a non-nil error, a *PathError is returned
+// using the given path.
+func callMethod(value reflect.Value, fieldName string, path string) (reflect.Value, bool, error) {
+	method := findMethod(value, strings.TrimSuffix(fieldName, "()"))
+	if !method.IsValid() {
+		return value, false, nil
+	}
+
+	methodType := method.Type()
+	if methodType.NumIn() != 0 || methodType.NumOut() == 0 || methodType.NumOut() > 2 ||
+		(methodType.NumOut() == 2 && methodType.Out(1) != errorType) {
+		return value, true, ErrInvalidMethod
+	}
+
+	out := method.Call(nil)
+	if len(out) == 2 && !out[1].IsNil() {
+		return value, true, &PathError{Path: path, Err: out[1].Interface().(error)}
+	}
+	return out[0], true, nil
+}
@@ -0,0 +1,187 @@
+package dipper_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+var errNoItems = errors.New("order has no items")
+
+type OrderItem struct {
+	Name  string
+	Price float64
+	Units int
+}
+
+func (i OrderItem) Total() float64 {
+	return i.Price * float64(i.Units)
+}
+
+type Order struct {
+	Customer Author
+	Items    []OrderItem
+}
+
+func (o *Order) Total() (float64, error) {
+	if len(o.Items) == 0 {
+		return 0, errNoItems
+	}
+	total := 0.0
+	for _, item := range o.Items {
+		total += item.Total()
+	}
+	return total, nil
+}
+
+func (o *Order) First() *OrderItem {
+	if len(o.Items) == 0 {
+		return nil
+	}
+	return &o.Items[0]
+}
+
+func (o Order) Count(int) int {
+	return len(o.Items)
+}
+
+func (o Order) Clear() {}
+
+func (o Order) Summary() (string, string) {
+	return "", ""
+}
+
+func getTestOrder() *Order {
+	return &Order{
+		Customer: Author{Name: "Umberto Eco"},
+		Items: []OrderItem{
+			{Name: "El nombre de la rosa", Price: 12.5, Units: 2},
+			{Name: "Baudolino", Price: 10, Units: 1},
+		},
+	}
+}
+
+func TestDipper_GetWithMethods(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "value receiver",
+			obj:       getTestOrder(),
+			attribute: "Items.1.Total()",
+			want:      10.0,
+		},
+		{
+			name:      "value receiver with filter",
+			obj:       getTestOrder(),
+			attribute: "Items[Name='El nombre de la rosa'].Total()",
+			want:      25.0,
+		},
+		{
+			name:      "pointer receiver with error",
+			obj:       getTestOrder(),
+			attribute: "Total()",
+			want:      35.0,
+		},
+		{
+			name:      "pointer receiver on unaddressable value",
+			obj:       *getTestOrder(),
+			attribute: "Total()",
+			want:      35.0,
+		},
+		{
+			name:      "fields of method result",
+			obj:       getTestOrder(),
+			attribute: "First().Name",
+			want:      "El nombre de la rosa",
+		},
+		{
+			name:      "method of method result",
+			obj:       getTestOrder(),
+			attribute: "First().Total()",
+			want:      25.0,
+		},
+		{
+			name:      "method on struct field",
+			obj:       getTestStruct(),
+			attribute: "Author.BirthDate.Year()",
+			want:      1932,
+		},
+		{
+			name:      "method error",
+			obj:       &Order{},
+			attribute: "Total()",
+			want:      &dipper.PathError{Path: "Total()", Err: errNoItems},
+		},
+		{
+			name:      "nested method error",
+			obj:       map[string]interface{}{"orders": []*Order{{}}},
+			attribute: "orders.0.Total()",
+			want:      &dipper.PathError{Path: "orders.0.Total()", Err: errNoItems},
+		},
+		{
+			name:      "method with arguments",
+			obj:       getTestOrder(),
+			attribute: "Count()",
+			want:      dipper.ErrInvalidMethod,
+		},
+		{
+			name:      "method without results",
+			obj:       getTestOrder(),
+			attribute: "Clear()",
+			want:      dipper.ErrInvalidMethod,
+		},
+		{
+			name:      "method with non-error second result",
+			obj:       getTestOrder(),
+			attribute: "Summary()",
+			want:      dipper.ErrInvalidMethod,
+		},
+		{
+			name:      "method not found",
+			obj:       getTestOrder(),
+			attribute: "Customer.Total()",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "map key ending in parentheses",
+			obj:       map[string]int{"Total()": 1},
+			attribute: "Total()",
+			want:      1,
+		},
+		{
+			name:      "disabled methods",
+			opts:      dipper.Options{DisableMethods: true},
+			obj:       getTestOrder(),
+			attribute: "Total()",
+			want:      dipper.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(tt.opts)
+			got := d.Get(tt.obj, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_SetWithMethods(t *testing.T) {
+	order := getTestOrder()
+
+	err := dipper.Set(order, "First().Name", "Baudolino")
+	if err != dipper.ErrMethodNotSettable {
+		t.Errorf("Set() = %v, want %v", err, dipper.ErrMethodNotSettable)
+	}
+	if order.Items[0].Name != "El nombre de la rosa" {
+		t.Errorf("Set() => Value changed to %v", order.Items[0].Name)
+	}
+}
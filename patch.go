@@ -0,0 +1,464 @@
+package dipper
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// PatchOp is a JSON Patch operation, as defined in RFC 6902. Paths are JSON
+// Pointers (see JSONPointer).
+type PatchOp struct {
+	// Op is the operation: "add", "remove", "replace", "move", "copy" or
+	// "test".
+	Op string `json:"op"`
+	// Path is the JSON Pointer of the target location.
+	Path string `json:"path"`
+	// From is the JSON Pointer of the source location of "move" and "copy"
+	// operations.
+	From string `json:"from,omitempty"`
+	// Value is the value of "add", "replace" and "test" operations.
+	Value interface{} `json:"value"`
+}
+
+// ApplyPatch applies the given JSON Patch operations to obj, which must be
+// addressable. Operation paths are always JSON Pointers, regardless of the
+// Dipper syntax, but the other options (e.g. TagName) are used to access the
+// values.
+// Values that cannot be assigned to the target location (e.g. a float64 from
+// a JSON document set to an int field) are converted by encoding them as JSON
+// and decoding them into the target type. The value of a "test" operation is
+// not converted: it must have the same JSON encoding as the target value, with
+// the same object keys and equal numbers.
+// Operations are applied in order. If any of them fails, the changes made by
+// the previous operations are reverted and the error is returned, so obj is
+// left unchanged. A failed "test" operation returns ErrTestFailed, and an
+// unknown or invalid operation returns ErrInvalidPatch.
+//
+// Example:
+//
+//	var ops []dipper.PatchOp
+//	if err := json.NewDecoder(r.Body).Decode(&ops); err != nil {
+//	    return err
+//	}
+//	if err := my_dipper.ApplyPatch(&book, ops); err != nil {
+//	    return err
+//	}
+func (d *Dipper) ApplyPatch(obj interface{}, ops []PatchOp) error {
+	p := &patcher{d: d.withSyntax(JSONPointer), obj: obj}
+
+	for _, op := range ops {
+		if err := p.apply(op); err != nil {
+			p.rollback()
+			return err
+		}
+	}
+	return nil
+}
+
+// withSyntax returns a copy of the Dipper using the given syntax.
+func (d *Dipper) withSyntax(syntax Syntax) *Dipper {
+	opts := d.opts
+	opts.Syntax = syntax
+	return New(opts)
+}
+
+// patcher applies JSON Patch operations to an object, keeping the functions
+// needed to revert the changes made.
+type patcher struct {
+	d    *Dipper
+	obj  interface{}
+	undo []func() error
+}
+
+// apply applies a single operation.
+func (p *patcher) apply(op PatchOp) error {
+	switch op.Op {
+	case "add":
+		return p.add(op.Path, op.Value)
+
+	case "remove":
+		return p.remove(op.Path)
+
+	case "replace":
+		if _, err := p.get(op.Path); err != nil {
+			return err
+		}
+		return p.set(op.Path, op.Value)
+
+	case "move":
+		if op.From == op.Path {
+			_, err := p.get(op.From)
+			return err
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return ErrInvalidPatch
+		}
+		value, err := p.get(op.From)
+		if err != nil {
+			return err
+		}
+		if err := p.remove(op.From); err != nil {
+			return err
+		}
+		return p.add(op.Path, value.Interface())
+
+	case "copy":
+		value, err := p.get(op.From)
+		if err != nil {
+			return err
+		}
+		return p.add(op.Path, copyValue(value).Interface())
+
+	case "test":
+		value, err := p.get(op.Path)
+		if err != nil {
+			return err
+		}
+		if !sameJSON(value.Interface(), op.Value) {
+			return ErrTestFailed
+		}
+		return nil
+	}
+
+	return ErrInvalidPatch
+}
+
+// rollback reverts the changes made by the applied operations.
+func (p *patcher) rollback() {
+	for i := len(p.undo) - 1; i >= 0; i-- {
+		_ = p.undo[i]()
+	}
+	p.undo = nil
+}
+
+// get returns the value referenced by the given path.
+func (p *patcher) get(path string) (reflect.Value, error) {
+	value := reflect.ValueOf(p.obj)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	value, err := p.d.getReflectValue(value, path, nil)
+	if err == nil {
+		value, err = loadAtomicValue(value)
+	}
+	return value, err
+}
+
+// add adds the value to the given path. If the parent of the path is a slice,
+// the value is inserted at the given index (or appended if the index is "-").
+// Otherwise, the value is set, replacing the existing value if any.
+func (p *patcher) add(path string, value interface{}) error {
+	if path == "" {
+		return p.set(path, value)
+	}
+
+	parentPath, field := splitPointer(path)
+	parent, err := p.get(parentPath)
+	if err != nil {
+		return err
+	}
+
+	slice, raw, err := p.elements(parent, parentPath)
+	if err != nil {
+		return err
+	}
+	if slice.Kind() != reflect.Slice {
+		return p.set(path, value)
+	}
+
+	index := slice.Len()
+	if field != "-" {
+		if index, err = pointerIndex(field); err != nil {
+			return err
+		}
+		if index > slice.Len() {
+			return ErrIndexOutOfRange
+		}
+	}
+
+	elem, err := convertValue(value, slice.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	newSlice := reflect.MakeSlice(slice.Type(), 0, slice.Len()+1)
+	newSlice = reflect.AppendSlice(newSlice, slice.Slice(0, index))
+	newSlice = reflect.Append(newSlice, valueOf(elem, slice.Type().Elem()))
+	newSlice = reflect.AppendSlice(newSlice, slice.Slice(index, slice.Len()))
+	return p.setElements(parentPath, parent, raw, newSlice)
+}
+
+// remove removes the value referenced by the given path. Slice elements are
+// removed from the slice, map keys are deleted, and other values are set to
+// their zero value.
+func (p *patcher) remove(path string) error {
+	if _, err := p.get(path); err != nil {
+		return err
+	}
+
+	parentPath, field := splitPointer(path)
+	parent, err := p.get(parentPath)
+	if err != nil {
+		return err
+	}
+
+	slice, raw, err := p.elements(parent, parentPath)
+	if err != nil {
+		return err
+	}
+	if path == "" || slice.Kind() != reflect.Slice {
+		return p.set(path, Delete)
+	}
+
+	index, err := pointerIndex(field)
+	if err != nil {
+		return err
+	}
+
+	newSlice := reflect.MakeSlice(slice.Type(), 0, slice.Len()-1)
+	newSlice = reflect.AppendSlice(newSlice, slice.Slice(0, index))
+	newSlice = reflect.AppendSlice(newSlice, slice.Slice(index+1, slice.Len()))
+	return p.setElements(parentPath, parent, raw, newSlice)
+}
+
+// elements returns the value held by the given parent, and the raw JSON value
+// if the parent holds a raw JSON document, which is returned decoded. Raw JSON
+// documents are not handled as byte slices, so only the arrays they hold are
+// returned as slices.
+func (p *patcher) elements(parent reflect.Value, parentPath string) (reflect.Value, reflect.Value, error) {
+	raw := p.d.getRawJSON(parent)
+	if !raw.IsValid() {
+		return getElemSafe(parent), raw, nil
+	}
+	doc, err := decodeRawJSON(raw, parentPath)
+	if err != nil {
+		return reflect.Value{}, raw, err
+	}
+	return getElemSafe(doc), raw, nil
+}
+
+// setElements sets the new slice to the given parent path, encoding it if the
+// parent holds a raw JSON document.
+func (p *patcher) setElements(parentPath string, parent, raw, newSlice reflect.Value) error {
+	if !raw.IsValid() {
+		return p.set(parentPath, newSlice.Interface())
+	}
+	value, err := rawJSONValue(parent, raw, newSlice.Interface(), parentPath)
+	if err != nil {
+		return err
+	}
+	return p.set(parentPath, value)
+}
+
+// set sets the value of the given path, converting it to the type of the
+// target location if needed, and keeps the function to revert the change.
+func (p *patcher) set(path string, value interface{}) error {
+	var undo func() error
+
+	current, err := p.get(path)
+	switch {
+	case err == nil:
+		old := current.Interface()
+		undo = func() error { return p.d.Set(p.obj, path, old) }
+		if value != Delete {
+			if value, err = convertValue(value, current.Type()); err != nil {
+				return err
+			}
+		}
+
+	case err == ErrNotFound:
+		// The path is a new map key
+		parentPath, _ := splitPointer(path)
+		parent, err := p.get(parentPath)
+		if err != nil {
+			return err
+		}
+
+		m := getElemSafe(parent)
+		if m.Kind() == reflect.Map {
+			if value, err = convertValue(value, m.Type().Elem()); err != nil {
+				return err
+			}
+			if m.IsNil() {
+				undo = func() error { return p.d.Set(p.obj, parentPath, Zero) }
+				break
+			}
+		}
+		undo = func() error { return p.d.Set(p.obj, path, Delete) }
+
+	default:
+		return err
+	}
+
+	if err := p.d.Set(p.obj, path, value); err != nil {
+		return err
+	}
+	p.undo = append(p.undo, undo)
+	return nil
+}
+
+// splitPointer returns the pointer to the parent of the given JSON Pointer and
+// its last unescaped field.
+func splitPointer(pointer string) (string, string) {
+	i := strings.LastIndexByte(pointer, '/')
+	if i < 0 {
+		return "", ""
+	}
+	return pointer[:i], unescapePointer(pointer[i+1:])
+}
+
+// convertValue returns the given value converted to type t. Values that are not
+// assignable to t are encoded as JSON and decoded into a new value of type t,
+// returning ErrTypesDoNotMatch if that is not possible. A nil value is
+// converted to the zero value of t.
+func convertValue(value interface{}, t reflect.Type) (interface{}, error) {
+	if t.Kind() == reflect.Interface {
+		return value, nil
+	}
+	if value == nil {
+		return reflect.Zero(t).Interface(), nil
+	}
+	if reflect.TypeOf(value).AssignableTo(t) {
+		return value, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, ErrTypesDoNotMatch
+	}
+	converted := reflect.New(t)
+	if err := json.Unmarshal(data, converted.Interface()); err != nil {
+		return nil, ErrTypesDoNotMatch
+	}
+	return converted.Elem().Interface(), nil
+}
+
+// valueOf returns the reflect.Value of the given value, or the zero value of
+// type t if the value is nil.
+func valueOf(value interface{}, t reflect.Type) reflect.Value {
+	if value == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(value)
+}
+
+// jsonEqual returns true if both values are deeply equal, or if their JSON
+// encodings are equal (e.g. 1 and 1.0).
+func jsonEqual(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// sameJSON returns true if both values are encoded as the same JSON value, as
+// required by the test operation: objects with the same members, arrays with
+// the same elements in the same order, and numbers with the same value (e.g. 1
+// and 1.0).
+func sameJSON(a, b interface{}) bool {
+	docA, errA := genericJSON(a)
+	docB, errB := genericJSON(b)
+	return errA == nil && errB == nil && reflect.DeepEqual(docA, docB)
+}
+
+// genericJSON encodes the given value as JSON and decodes it into an
+// interface{}, with its numbers in their shortest form.
+func genericJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := unmarshalUseNumber(data, &doc); err != nil {
+		return nil, err
+	}
+	return normalizeNumbers(doc), nil
+}
+
+// normalizeNumbers replaces the json.Number values of the given document with
+// their shortest form, so equal numbers are deeply equal.
+func normalizeNumbers(doc interface{}) interface{} {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = normalizeNumbers(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = normalizeNumbers(elem)
+		}
+	case json.Number:
+		if f, ok := new(big.Float).SetPrec(256).SetString(v.String()); ok {
+			return json.Number(f.Text('g', -1))
+		}
+	}
+	return doc
+}
+
+// copyValue returns a deep copy of the given value, so it does not share maps,
+// slices or pointers with the original value. Unexported struct fields are
+// copied as they are.
+func copyValue(value reflect.Value) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		c := reflect.New(value.Type().Elem())
+		c.Elem().Set(copyValue(value.Elem()))
+		return c
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		c := reflect.New(value.Type()).Elem()
+		c.Set(copyValue(value.Elem()))
+		return c
+
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		c := reflect.MakeMapWithSize(value.Type(), value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), copyValue(iter.Value()))
+		}
+		return c
+
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		c := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			c.Index(i).Set(copyValue(value.Index(i)))
+		}
+		return c
+
+	case reflect.Array:
+		c := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			c.Index(i).Set(copyValue(value.Index(i)))
+		}
+		return c
+
+	case reflect.Struct:
+		c := reflect.New(value.Type()).Elem()
+		c.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(copyValue(value.Field(i)))
+			}
+		}
+		return c
+	}
+	return value
+}
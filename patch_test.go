@@ -0,0 +1,251 @@
+package dipper_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestDipper_ApplyPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    dipper.Options
+		obj     interface{}
+		ops     string
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "add map key",
+			obj:  &map[string]interface{}{"a": 1},
+			ops:  `[{"op": "add", "path": "/b", "value": {"c": [true]}}]`,
+			want: &map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": []interface{}{true}}},
+		},
+		{
+			name: "add existing map key",
+			obj:  &map[string]interface{}{"a": 1},
+			ops:  `[{"op": "add", "path": "/a", "value": 2}]`,
+			want: &map[string]interface{}{"a": 2.0},
+		},
+		{
+			name: "insert slice element",
+			obj:  &Book{GenreNames: []string{"Mystery", "Crime"}},
+			ops:  `[{"op": "add", "path": "/GenreNames/1", "value": "Historical"}]`,
+			want: &Book{GenreNames: []string{"Mystery", "Historical", "Crime"}},
+		},
+		{
+			name: "append slice element",
+			obj:  &Book{},
+			ops:  `[{"op": "add", "path": "/Genres/-", "value": {"ID": 3, "Name": "Sci-Fi"}}]`,
+			want: &Book{Genres: []Genre{{ID: 3, Name: "Sci-Fi"}}},
+		},
+		{
+			name: "add to nil map",
+			opts: dipper.Options{TagName: "json"},
+			obj:  &Book{},
+			ops:  `[{"op": "add", "path": "/extra/pages", "value": 512}]`,
+			want: &Book{Extra: map[string]interface{}{"pages": 512.0}},
+		},
+		{
+			name: "remove",
+			obj:  &Book{Title: "Dune", GenreNames: []string{"a", "b", "c"}, Extra: map[string]interface{}{"x": 1}},
+			ops: `[
+				{"op": "remove", "path": "/GenreNames/1"},
+				{"op": "remove", "path": "/Extra/x"},
+				{"op": "remove", "path": "/Title"}
+			]`,
+			want: &Book{GenreNames: []string{"a", "c"}, Extra: map[string]interface{}{}},
+		},
+		{
+			name: "add to raw JSON array",
+			obj:  &Event{Payload: json.RawMessage(`[1, 2]`), Extra: rawMessagePtr(`[]`)},
+			ops: `[
+				{"op": "add", "path": "/Payload/-", "value": 3},
+				{"op": "add", "path": "/Payload/0", "value": 0},
+				{"op": "add", "path": "/Extra/-", "value": {"a": true}}
+			]`,
+			want: &Event{Payload: json.RawMessage(`[0,1,2,3]`), Extra: rawMessagePtr(`[{"a":true}]`)},
+		},
+		{
+			name: "add to raw JSON array keeps numbers",
+			obj:  &Event{Payload: json.RawMessage(`[12345678901234567890, 1.50]`)},
+			ops:  `[{"op": "add", "path": "/Payload/0", "value": 0}]`,
+			want: &Event{Payload: json.RawMessage(`[0,12345678901234567890,1.50]`)},
+		},
+		{
+			name: "remove from raw JSON",
+			obj:  &Event{Payload: json.RawMessage(`{"a": 1, "b": [1, 2, 3]}`), Extra: rawMessagePtr(`[1, 2]`)},
+			ops: `[
+				{"op": "remove", "path": "/Payload/a"},
+				{"op": "remove", "path": "/Payload/b/1"},
+				{"op": "remove", "path": "/Extra/0"}
+			]`,
+			want: &Event{Payload: json.RawMessage(`{"b":[1,3]}`), Extra: rawMessagePtr(`[2]`)},
+		},
+		{
+			name: "replace with conversion",
+			obj:  &Book{Year: 1965, Author: Author{Name: "Frank"}},
+			ops: `[
+				{"op": "replace", "path": "/Year", "value": 1966},
+				{"op": "replace", "path": "/Author", "value": {"Name": "Frank Herbert"}}
+			]`,
+			want: &Book{Year: 1966, Author: Author{Name: "Frank Herbert"}},
+		},
+		{
+			name: "replace root",
+			obj:  &Book{Title: "Dune"},
+			ops:  `[{"op": "replace", "path": "", "value": {"Title": "1984"}}]`,
+			want: &Book{Title: "1984"},
+		},
+		{
+			name: "move",
+			obj:  &Book{Title: "Dune", Extra: map[string]interface{}{"subtitle": "Dune Messiah"}},
+			ops:  `[{"op": "move", "from": "/Extra/subtitle", "path": "/Title"}]`,
+			want: &Book{Title: "Dune Messiah", Extra: map[string]interface{}{}},
+		},
+		{
+			name: "move slice element",
+			obj:  &[]interface{}{1, 2, 3},
+			ops:  `[{"op": "move", "from": "/0", "path": "/-"}]`,
+			want: &[]interface{}{2, 3, 1},
+		},
+		{
+			name: "copy",
+			obj:  &map[string]interface{}{"a": map[string]interface{}{"b": 1}},
+			ops: `[
+				{"op": "copy", "from": "/a", "path": "/c"},
+				{"op": "replace", "path": "/c/b", "value": 2}
+			]`,
+			want: &map[string]interface{}{"a": map[string]interface{}{"b": 1}, "c": map[string]interface{}{"b": 2.0}},
+		},
+		{
+			name: "test",
+			obj:  &Book{Title: "Dune", Year: 1965, Extra: map[string]interface{}{"pages": 412}},
+			ops: `[
+				{"op": "test", "path": "/Year", "value": 1965},
+				{"op": "test", "path": "/Extra", "value": {"pages": 412}},
+				{"op": "replace", "path": "/Title", "value": "Dune Messiah"}
+			]`,
+			want: &Book{Title: "Dune Messiah", Year: 1965, Extra: map[string]interface{}{"pages": 412}},
+		},
+		{
+			name: "test with equal numbers",
+			obj:  &Event{Payload: json.RawMessage(`{"id": 1.0, "size": 2.50}`)},
+			ops: `[
+				{"op": "test", "path": "/Payload/id", "value": 1},
+				{"op": "test", "path": "/Payload", "value": {"size": 25e-1, "id": 1e0}}
+			]`,
+			want: &Event{Payload: json.RawMessage(`{"id": 1.0, "size": 2.50}`)},
+		},
+		{
+			name:    "test with extra keys",
+			obj:     &Book{Author: Author{Name: "a"}},
+			ops:     `[{"op": "test", "path": "/Author", "value": {"name": "a", "birth_date": "0001-01-01T00:00:00Z", "bogus": 3}}]`,
+			want:    &Book{Author: Author{Name: "a"}},
+			wantErr: dipper.ErrTestFailed,
+		},
+		{
+			name:    "test with case-mismatched keys",
+			obj:     &Book{Author: Author{Name: "a"}},
+			ops:     `[{"op": "test", "path": "/Author", "value": {"Name": "a", "birth_date": "0001-01-01T00:00:00Z"}}]`,
+			want:    &Book{Author: Author{Name: "a"}},
+			wantErr: dipper.ErrTestFailed,
+		},
+		{
+			name:    "test with missing keys",
+			obj:     &Book{Author: Author{Name: "a"}},
+			ops:     `[{"op": "test", "path": "/Author", "value": {"name": "a"}}]`,
+			want:    &Book{Author: Author{Name: "a"}},
+			wantErr: dipper.ErrTestFailed,
+		},
+		{
+			name:    "test with different numbers",
+			obj:     &Book{Year: 1965},
+			ops:     `[{"op": "test", "path": "/Year", "value": 1965.5}]`,
+			want:    &Book{Year: 1965},
+			wantErr: dipper.ErrTestFailed,
+		},
+		{
+			name:    "failed test reverts changes",
+			obj:     &Book{Title: "Dune", Year: 1965},
+			ops:     `[{"op": "replace", "path": "/Title", "value": "1984"}, {"op": "test", "path": "/Year", "value": 1949}]`,
+			want:    &Book{Title: "Dune", Year: 1965},
+			wantErr: dipper.ErrTestFailed,
+		},
+		{
+			name: "failed operation reverts changes",
+			obj: &Book{
+				Title:      "Dune",
+				GenreNames: []string{"Sci-Fi"},
+				Extra:      map[string]interface{}{"a": 1},
+			},
+			ops: `[
+				{"op": "add", "path": "/GenreNames/0", "value": "Adventure"},
+				{"op": "remove", "path": "/Extra/a"},
+				{"op": "add", "path": "/Extra/b", "value": 2},
+				{"op": "replace", "path": "/Title", "value": "Dune Messiah"},
+				{"op": "move", "from": "/Extra/b", "path": "/Any"},
+				{"op": "replace", "path": "/Publisher", "value": "Chilton"}
+			]`,
+			want: &Book{
+				Title:      "Dune",
+				GenreNames: []string{"Sci-Fi"},
+				Extra:      map[string]interface{}{"a": 1},
+			},
+			wantErr: dipper.ErrNotFound,
+		},
+		{
+			name:    "failed add to nil map reverts changes",
+			obj:     &Book{},
+			ops:     `[{"op": "add", "path": "/Extra/a", "value": 1}, {"op": "remove", "path": "/Extra/b"}]`,
+			want:    &Book{},
+			wantErr: dipper.ErrNotFound,
+		},
+		{
+			name:    "index out of range",
+			obj:     &Book{GenreNames: []string{"Sci-Fi"}},
+			ops:     `[{"op": "add", "path": "/GenreNames/2", "value": "Drama"}]`,
+			want:    &Book{GenreNames: []string{"Sci-Fi"}},
+			wantErr: dipper.ErrIndexOutOfRange,
+		},
+		{
+			name:    "types do not match",
+			obj:     &Book{Year: 1965},
+			ops:     `[{"op": "replace", "path": "/Year", "value": "1966"}]`,
+			want:    &Book{Year: 1965},
+			wantErr: dipper.ErrTypesDoNotMatch,
+		},
+		{
+			name:    "move into child",
+			obj:     &map[string]interface{}{"a": map[string]interface{}{}},
+			ops:     `[{"op": "move", "from": "/a", "path": "/a/b"}]`,
+			want:    &map[string]interface{}{"a": map[string]interface{}{}},
+			wantErr: dipper.ErrInvalidPatch,
+		},
+		{
+			name:    "unknown operation",
+			obj:     &Book{},
+			ops:     `[{"op": "merge", "path": "/Title"}]`,
+			want:    &Book{},
+			wantErr: dipper.ErrInvalidPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ops []dipper.PatchOp
+			if err := json.Unmarshal([]byte(tt.ops), &ops); err != nil {
+				t.Fatal(err)
+			}
+
+			err := dipper.New(tt.opts).ApplyPatch(tt.obj, ops)
+			if err != tt.wantErr {
+				t.Fatalf("ApplyPatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.obj, tt.want) {
+				t.Errorf("ApplyPatch() obj = %#v, want %#v", tt.obj, tt.want)
+			}
+		})
+	}
+}
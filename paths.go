@@ -0,0 +1,105 @@
+package dipper
+
+import (
+	"reflect"
+)
+
+// PathInfo describes an attribute exposed by a type, as returned by
+// Dipper.PathsOf().
+type PathInfo struct {
+	// Path is the attribute, using "[*]" as a placeholder for slice and array
+	// indexes and "{key}" for map keys (e.g. "Books[*].Title" or
+	// "Settings.{key}"). With the JSONPointer syntax, the placeholders are
+	// written as fields (e.g. "/Books/*/Title").
+	Path string
+	// Type is the Go type of the value.
+	Type reflect.Type
+	// Tag is the struct tag of the field, if the value is a struct field.
+	Tag reflect.StructTag
+	// Doc is the field documentation given in the "doc" struct tag, if any.
+	// Go comments are not available at runtime.
+	Doc string
+}
+
+// PathsOf returns every attribute exposed by the given type, without the need
+// of a value, in field order. Struct fields are named as they are accessed in
+// attributes (e.g. according to the TagName option), and the elements of
+// slices, arrays and maps are described with placeholders (see PathInfo.Path).
+// Pointers are described by the type they point to. Interfaces, custom
+// containers, []byte values and structs without exported fields (e.g.
+// time.Time) are described, but not their children, since they depend on the
+// values. Recursive types are described up to the first repetition.
+//
+// Example:
+//
+//	for _, p := range my_dipper.PathsOf(reflect.TypeOf(Config{})) {
+//	    fmt.Printf("%s (%s): %s\n", p.Path, p.Type, p.Doc)
+//	}
+func (d *Dipper) PathsOf(t reflect.Type) []PathInfo {
+	var paths []PathInfo
+	d.pathsOf(t, "", map[reflect.Type]bool{}, &paths)
+	return paths
+}
+
+// pathsOf adds the attributes exposed by the children of the given type to the
+// result. expanding holds the types whose children are being described, to
+// detect recursive types.
+func (d *Dipper) pathsOf(t reflect.Type, path string, expanding map[reflect.Type]bool, paths *[]PathInfo) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || expanding[t] || d.isContainerType(t) {
+		return
+	}
+	expanding[t] = true
+	defer delete(expanding, t)
+
+	add := func(childPath string, childType reflect.Type, tag reflect.StructTag) {
+		*paths = append(*paths, PathInfo{Path: childPath, Type: childType, Tag: tag, Doc: tag.Get("doc")})
+		d.pathsOf(childType, childPath, expanding, paths)
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if !hasExportedFields(t) {
+			return
+		}
+		for _, f := range cachedStructFields(t, d.opts.TagName).list {
+			if f.inline || (!f.exported && !d.opts.AllowUnexported) {
+				continue
+			}
+			sf := t.FieldByIndex(f.index)
+			add(d.joinAttribute(path, f.name), sf.Type, sf.Tag)
+		}
+
+	case reflect.Map:
+		add(d.joinAttribute(path, "{key}"), t.Elem(), "")
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		if d.opts.Syntax == JSONPointer {
+			add(d.joinAttribute(path, "*"), t.Elem(), "")
+		} else {
+			add(path+"[*]", t.Elem(), "")
+		}
+	}
+}
+
+// isContainerType returns true if the values of the given type (or a pointer
+// to it) are custom containers, whose children can only be known from a value.
+func (d *Dipper) isContainerType(t reflect.Type) bool {
+	for _, typ := range []reflect.Type{t, reflect.PtrTo(t)} {
+		if _, ok := d.opts.Resolvers[typ]; ok {
+			return true
+		}
+		if _, ok := builtinResolvers[typ]; ok {
+			return true
+		}
+		if typ.Kind() != reflect.Interface && typ.Implements(containerType) {
+			return true
+		}
+	}
+	return false
+}
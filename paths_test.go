@@ -0,0 +1,104 @@
+package dipper_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flusflas/dipper"
+)
+
+type Library struct {
+	Name     string            `json:"name" doc:"Name of the library"`
+	Books    []*Book           `json:"books"`
+	Settings map[string]string `json:"settings" doc:"Free-form settings"`
+	Parent   *Library          `json:"parent"`
+}
+
+func TestDipper_PathsOf(t *testing.T) {
+	var (
+		stringType = reflect.TypeOf("")
+		intType    = reflect.TypeOf(0)
+		timeType   = reflect.TypeOf(time.Time{})
+	)
+
+	tests := []struct {
+		name string
+		opts dipper.Options
+		t    reflect.Type
+		want []dipper.PathInfo
+	}{
+		{
+			name: "struct",
+			t:    reflect.TypeOf(Author{}),
+			want: []dipper.PathInfo{
+				{Path: "Name", Type: stringType, Tag: `json:"name"`},
+				{Path: "BirthDate", Type: timeType, Tag: `json:"birth_date"`},
+			},
+		},
+		{
+			name: "slices, maps, pointers and recursive types",
+			opts: dipper.Options{TagName: "json"},
+			t:    reflect.TypeOf(&Library{}),
+			want: []dipper.PathInfo{
+				{Path: "name", Type: stringType, Tag: `json:"name" doc:"Name of the library"`, Doc: "Name of the library"},
+				{Path: "books", Type: reflect.TypeOf([]*Book{}), Tag: `json:"books"`},
+				{Path: "books[*]", Type: reflect.TypeOf(&Book{})},
+				{Path: "books[*].title", Type: stringType, Tag: `json:"title"`},
+				{Path: "books[*].year", Type: intType, Tag: `json:"year"`},
+				{Path: "books[*].author", Type: reflect.TypeOf(Author{}), Tag: `json:"author"`},
+				{Path: "books[*].author.name", Type: stringType, Tag: `json:"name"`},
+				{Path: "books[*].author.birth_date", Type: timeType, Tag: `json:"birth_date"`},
+				{Path: "books[*].genre_names", Type: reflect.TypeOf([]string{}), Tag: `json:"genre_names"`},
+				{Path: "books[*].genre_names[*]", Type: stringType},
+				{Path: "books[*].genres", Type: reflect.TypeOf([]Genre{}), Tag: `json:"genres"`},
+				{Path: "books[*].genres[*]", Type: reflect.TypeOf(Genre{})},
+				{Path: "books[*].genres[*].id", Type: intType, Tag: `json:"id"`},
+				{Path: "books[*].genres[*].name", Type: stringType, Tag: `json:"name"`},
+				{Path: "books[*].genres[*].description", Type: stringType, Tag: `json:"description"`},
+				{Path: "books[*].extra", Type: reflect.TypeOf(map[string]interface{}{}), Tag: `json:"extra"`},
+				{Path: "books[*].extra.{key}", Type: reflect.TypeOf((*interface{})(nil)).Elem()},
+				{Path: "books[*].any", Type: reflect.TypeOf((*interface{})(nil)).Elem(), Tag: `json:"any"`},
+				{Path: "books[*].isbn", Type: stringType, Tag: `json:"isbn"`},
+				{Path: "settings", Type: reflect.TypeOf(map[string]string{}), Tag: `json:"settings" doc:"Free-form settings"`, Doc: "Free-form settings"},
+				{Path: "settings.{key}", Type: stringType},
+				{Path: "parent", Type: reflect.TypeOf(&Library{}), Tag: `json:"parent"`},
+			},
+		},
+		{
+			name: "JSON pointer syntax",
+			opts: dipper.Options{Syntax: dipper.JSONPointer},
+			t:    reflect.TypeOf(map[string][]Route{}),
+			want: []dipper.PathInfo{
+				{Path: "/{key}", Type: reflect.TypeOf([]Route{})},
+				{Path: "/{key}/*", Type: reflect.TypeOf(Route{})},
+				{Path: "/{key}/*/Path", Type: stringType, Tag: `json:"path"`},
+				{Path: "/{key}/*/Backend", Type: stringType, Tag: `json:"backend"`},
+			},
+		},
+		{
+			name: "opaque values",
+			t:    reflect.TypeOf(struct{ B []byte }{}),
+			want: []dipper.PathInfo{{Path: "B", Type: reflect.TypeOf([]byte{})}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dipper.New(tt.opts).PathsOf(tt.t)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PathsOf() = %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathsOf_ValidAttributes(t *testing.T) {
+	book := getTestStruct()
+	for _, p := range dipper.PathsOf(reflect.TypeOf(book)) {
+		attribute := strings.NewReplacer("[*]", ".0", "{key}", "foo").Replace(p.Path)
+		if err := dipper.Error(dipper.Get(book, attribute)); err != nil {
+			t.Errorf("Get(%q) error = %v", attribute, err)
+		}
+	}
+}
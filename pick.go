@@ -0,0 +1,276 @@
+package dipper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// selection is a tree of the values selected by the attributes given to
+// Pick() or Omit(), indexed by the names used to access them.
+type selection struct {
+	// all is true if the whole value is selected
+	all      bool
+	children map[string]*selection
+}
+
+// Pick returns a deep copy of obj, of the same type, with only the values
+// referenced by the given attributes. Struct fields that are not selected are
+// set to their zero value, map keys are removed, and slice elements are
+// removed (array elements are set to their zero value).
+// Attributes can contain wildcards ("[*]", or "*" with the JSONPointer
+// syntax) to select the remaining attribute in every element of a slice or map
+// (e.g. "Books[*].Title"), and filter expressions to select the first matching
+// slice element. Attributes that do not reference any value are ignored, and
+// other errors are returned in a *PathError with the attribute.
+// Values held by custom containers, sync.Map, atomic.Value and raw JSON values
+// are copied as a whole if any of their children is selected.
+//
+// Example:
+//
+//	picked, err := my_dipper.Pick(library, "Name", "Books[*].Title")
+//	if err != nil {
+//	    return err
+//	}
+//	response := picked.(*Library)
+func (d *Dipper) Pick(obj interface{}, attributes ...string) (interface{}, error) {
+	return d.filterCopy(obj, attributes, false)
+}
+
+// Omit returns a deep copy of obj, of the same type, without the values
+// referenced by the given attributes, which are removed as Dipper.Pick()
+// removes the values that are not selected. Attributes are written as in
+// Dipper.Pick().
+//
+// Example:
+//
+//	safe, err := my_dipper.Omit(user, "Password", "Sessions[*].Token")
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("user: %+v", safe)
+func (d *Dipper) Omit(obj interface{}, attributes ...string) (interface{}, error) {
+	return d.filterCopy(obj, attributes, true)
+}
+
+// filterCopy returns a deep copy of obj with only the values referenced by the
+// given attributes, or without them if omit is true.
+func (d *Dipper) filterCopy(obj interface{}, attributes []string, omit bool) (interface{}, error) {
+	value := reflect.ValueOf(obj)
+	if !value.IsValid() {
+		return nil, nil
+	}
+
+	sel := &selection{}
+	for _, attribute := range attributes {
+		fields, err := d.splitFields(attribute)
+		if err != nil {
+			return nil, &PathError{Path: attribute, Err: err}
+		}
+		// Brackets on a root slice (e.g. "[1].Name") start with an empty field
+		if d.opts.Syntax != JSONPointer && len(fields) > 1 && fields[0] == "" {
+			fields = fields[1:]
+		}
+		if _, err := d.selectFields(sel, value, fields); err != nil {
+			return nil, &PathError{Path: attribute, Err: err}
+		}
+	}
+
+	if sel.all {
+		if omit {
+			return reflect.Zero(value.Type()).Interface(), nil
+		}
+		return copyValue(value).Interface(), nil
+	}
+	return d.filterValue(value, sel, omit).Interface(), nil
+}
+
+// selectFields adds the children of value referenced by the given fields to
+// the selection. It returns true if any value is selected.
+func (d *Dipper) selectFields(sel *selection, value reflect.Value, fields []string) (bool, error) {
+	if sel.all {
+		return true, nil
+	}
+	if len(fields) == 0 {
+		sel.all, sel.children = true, nil
+		return true, nil
+	}
+
+	children, value := d.children(value)
+	matches, err := d.matchChildren(value, children, fields[0])
+	if err != nil {
+		return false, err
+	}
+
+	selected := false
+	for _, child := range matches {
+		c := sel.children[child.name]
+		if c == nil {
+			c = &selection{}
+		}
+		ok, err := d.selectFields(c, child.value, fields[1:])
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			if sel.children == nil {
+				sel.children = map[string]*selection{}
+			}
+			sel.children[child.name] = c
+			selected = true
+		}
+	}
+	return selected, nil
+}
+
+// matchChildren returns the children of value referenced by the given field,
+// which can be a wildcard, a slice index, a filter expression or a name.
+func (d *Dipper) matchChildren(value reflect.Value, children []namedValue, field string) ([]namedValue, error) {
+	if field == d.wildcard() {
+		return children, nil
+	}
+
+	name := field
+	if d.opts.Syntax != JSONPointer && strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+		name = field[1 : len(field)-1]
+
+		elem := getElemSafe(value)
+		if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array {
+			i, err := d.filterIndex(elem, name)
+			if err != nil && !isNotFound(err) {
+				return nil, err
+			}
+			if i >= 0 && i < len(children) {
+				return children[i : i+1], nil
+			}
+			if err != nil {
+				return nil, nil
+			}
+		}
+	}
+
+	var folded []namedValue
+	for _, child := range children {
+		if child.name == name {
+			return []namedValue{child}, nil
+		}
+		if d.opts.CaseInsensitive && strings.EqualFold(child.name, name) {
+			folded = append(folded, child)
+		}
+	}
+	if len(folded) == 1 {
+		return folded, nil
+	}
+	return nil, nil
+}
+
+// filterValue returns a deep copy of value with only its selected children, or
+// without them if omit is true.
+func (d *Dipper) filterValue(value reflect.Value, sel *selection, omit bool) reflect.Value {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		c := reflect.New(value.Type().Elem())
+		c.Elem().Set(d.filterValue(value.Elem(), sel, omit))
+		return c
+
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		c := reflect.New(value.Type()).Elem()
+		c.Set(d.filterValue(value.Elem(), sel, omit))
+		return c
+	}
+
+	// The children of these values cannot be copied one by one
+	if r, _ := d.getResolver(value); r != nil || value.Type() == atomicValueType || d.getRawJSON(value).IsValid() {
+		return copyValue(value)
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		if !hasExportedFields(value.Type()) {
+			break
+		}
+		c := reflect.New(value.Type()).Elem()
+		if omit {
+			c.Set(copyValue(value))
+		}
+		for _, child := range d.structFieldValues(value) {
+			field, err := d.getStructField(c, child.name, true)
+			if err != nil {
+				continue
+			}
+			if v, ok := d.filterChild(child, sel, omit); ok {
+				field.Set(v)
+			} else {
+				field.Set(reflect.Zero(field.Type()))
+			}
+		}
+		return c
+
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		c := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for _, key := range sortedMapKeys(value) {
+			child := namedValue{name: fmt.Sprint(key.Interface()), value: value.MapIndex(key)}
+			if v, ok := d.filterChild(child, sel, omit); ok {
+				c.SetMapIndex(key, v)
+			}
+		}
+		return c
+
+	case reflect.Slice:
+		if value.IsNil() || value.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		c := reflect.MakeSlice(value.Type(), 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			child := namedValue{name: strconv.Itoa(i), value: value.Index(i)}
+			if v, ok := d.filterChild(child, sel, omit); ok {
+				c = reflect.Append(c, v)
+			}
+		}
+		return c
+
+	case reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		c := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.Len(); i++ {
+			child := namedValue{name: strconv.Itoa(i), value: value.Index(i)}
+			if v, ok := d.filterChild(child, sel, omit); ok {
+				c.Index(i).Set(v)
+			}
+		}
+		return c
+	}
+
+	return copyValue(value)
+}
+
+// filterChild returns the filtered copy of the given child of a selection, and
+// false if the child must be removed.
+func (d *Dipper) filterChild(child namedValue, sel *selection, omit bool) (reflect.Value, bool) {
+	c := sel.children[child.name]
+	switch {
+	case c == nil:
+		if omit {
+			return copyValue(child.value), true
+		}
+		return reflect.Value{}, false
+	case c.all:
+		if omit {
+			return reflect.Value{}, false
+		}
+		return copyValue(child.value), true
+	}
+	return d.filterValue(child.value, c, omit), true
+}
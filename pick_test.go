@@ -0,0 +1,190 @@
+package dipper_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestDipper_Pick(t *testing.T) {
+	book := getTestStruct()
+
+	tests := []struct {
+		name       string
+		opts       dipper.Options
+		obj        interface{}
+		attributes []string
+		want       interface{}
+		wantErr    error
+	}{
+		{
+			name:       "struct fields",
+			obj:        book,
+			attributes: []string{"Title", "Author.Name"},
+			want:       &Book{Title: book.Title, Author: Author{Name: "Umberto Eco"}},
+		},
+		{
+			name:       "wildcard",
+			obj:        book,
+			attributes: []string{"Genres[*].Name"},
+			want:       &Book{Genres: []Genre{{Name: "Mystery"}, {Name: "Crime"}}},
+		},
+		{
+			name:       "slice elements are removed",
+			obj:        book,
+			attributes: []string{"Genres[1]", "GenreNames.0"},
+			want:       &Book{Genres: []Genre{book.Genres[1]}, GenreNames: []string{"Mystery"}},
+		},
+		{
+			name:       "filter expression",
+			obj:        book,
+			attributes: []string{"Genres[Name='Crime'].ID"},
+			want:       &Book{Genres: []Genre{{ID: 1}}},
+		},
+		{
+			name:       "nested maps and promoted fields",
+			obj:        book,
+			attributes: []string{"Extra.foo", "ISBN"},
+			want: &Book{
+				Extra:       map[string]interface{}{"foo": map[string]int{"bar": 123}},
+				Publication: Publication{ISBN: "1234567890"},
+			},
+		},
+		{
+			name:       "map",
+			obj:        map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2, "d": 3}},
+			attributes: []string{"b.c"},
+			want:       map[string]interface{}{"b": map[string]interface{}{"c": 2}},
+		},
+		{
+			name:       "tag names and case insensitive",
+			opts:       dipper.Options{TagName: "json", CaseInsensitive: true},
+			obj:        book,
+			attributes: []string{"TITLE", "author.name"},
+			want:       &Book{Title: book.Title, Author: Author{Name: "Umberto Eco"}},
+		},
+		{
+			name:       "JSON Pointer syntax",
+			opts:       dipper.Options{Syntax: dipper.JSONPointer},
+			obj:        book,
+			attributes: []string{"/Genres/*/ID"},
+			want:       &Book{Genres: []Genre{{ID: 0}, {ID: 1}}},
+		},
+		{
+			name:       "missing attributes are ignored",
+			obj:        book,
+			attributes: []string{"Title", "Genres.5", "Publisher", "Genres[Name='Horror']"},
+			want:       &Book{Title: book.Title},
+		},
+		{
+			name:       "invalid filter expression",
+			obj:        book,
+			attributes: []string{"Genres[Name='Crime' or 1]"},
+			wantErr:    dipper.ErrInvalidFilterValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(tt.opts)
+			got, err := d.Pick(tt.obj, tt.attributes...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Pick() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) && tt.wantErr == nil {
+				t.Errorf("Pick() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_Pick_Copy(t *testing.T) {
+	book := getTestStruct()
+
+	got, err := dipper.Pick(book, "Genres")
+	if err != nil {
+		t.Fatalf("Pick() error = %v", err)
+	}
+	got.(*Book).Genres[0].Name = "Horror"
+
+	if book.Genres[0].Name != "Mystery" {
+		t.Errorf("Pick() result shares values with the original object")
+	}
+}
+
+func TestDipper_Omit(t *testing.T) {
+	omitted := func(edit func(*Book)) *Book {
+		b := getTestStruct()
+		edit(b)
+		return b
+	}
+
+	tests := []struct {
+		name       string
+		obj        interface{}
+		attributes []string
+		want       interface{}
+	}{
+		{
+			name:       "struct fields",
+			obj:        getTestStruct(),
+			attributes: []string{"Title", "Author.BirthDate", "ISBN"},
+			want: omitted(func(b *Book) {
+				b.Title = ""
+				b.Author.BirthDate = time.Time{}
+				b.ISBN = ""
+			}),
+		},
+		{
+			name:       "wildcard",
+			obj:        getTestStruct(),
+			attributes: []string{"Genres[*].Description"},
+			want: omitted(func(b *Book) {
+				b.Genres[0].Description = ""
+				b.Genres[1].Description = ""
+			}),
+		},
+		{
+			name:       "slice elements and map keys are removed",
+			obj:        getTestStruct(),
+			attributes: []string{"Genres[Name='Mystery']", "Extra.foo"},
+			want: omitted(func(b *Book) {
+				b.Genres = b.Genres[1:]
+				b.Extra = map[string]interface{}{}
+			}),
+		},
+		{
+			name:       "map",
+			obj:        map[string]interface{}{"user": "admin", "password": "secret"},
+			attributes: []string{"password"},
+			want:       map[string]interface{}{"user": "admin"},
+		},
+		{
+			name:       "root",
+			obj:        getTestStruct(),
+			attributes: []string{""},
+			want:       (*Book)(nil),
+		},
+		{
+			name:       "missing attributes are ignored",
+			obj:        getTestStruct(),
+			attributes: []string{"Publisher", "Genres.5"},
+			want:       getTestStruct(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dipper.Omit(tt.obj, tt.attributes...)
+			if err != nil {
+				t.Fatalf("Omit() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Omit() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package dipper
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Syntax is the notation used to write attributes.
+type Syntax int
+
+const (
+	// DotNotation is the default attribute syntax, where fields are separated
+	// by the Dipper separator (e.g. "Books.0.Title" or "Books[0].Title").
+	DotNotation Syntax = iota
+	// JSONPointer is the JSON Pointer syntax defined in RFC 6901, where each
+	// field is prefixed by "/" (e.g. "/Books/0/Title"). The characters "~"
+	// and "/" in field names are escaped as "~0" and "~1", respectively.
+	// Filter expressions and method calls are not available, and the field
+	// "-" refers to the position after the last element of a slice, which can
+	// only be used to append a new element with Dipper.Set().
+	JSONPointer
+)
+
+// fieldSplitter is the interface implemented by the types that iterate the
+// fields of an attribute.
+type fieldSplitter interface {
+	HasMore() bool
+	Next() (string, int)
+	Parsed() string
+	Remaining() string
+	CountRemaining() int
+}
+
+// newSplitter returns the fieldSplitter used to iterate the fields of the given
+// attribute, according to the Dipper syntax.
+func (d *Dipper) newSplitter(attribute string) (fieldSplitter, error) {
+	if d.opts.Syntax != JSONPointer {
+		return newAttributeSplitter(attribute, d.opts.Separator), nil
+	}
+	if !validPointer(attribute) {
+		return nil, ErrInvalidPointer
+	}
+	return newPointerSplitter(attribute), nil
+}
+
+// joinAttribute returns the attribute of the child with the given name of the
+// value referenced by the parent attribute, according to the Dipper syntax.
+func (d *Dipper) joinAttribute(parent, name string) string {
+	switch {
+	case d.opts.Syntax == JSONPointer:
+		return parent + "/" + escapePointer(name)
+	case parent == "":
+		return name
+	}
+	return parent + d.opts.Separator + name
+}
+
+// pointerSplitter iterates the unescaped fields of a JSON Pointer.
+type pointerSplitter struct {
+	s        string
+	index    int
+	scanned  int
+	fieldEnd int
+}
+
+// newPointerSplitter returns a new pointerSplitter instance. The pointer must
+// be valid.
+func newPointerSplitter(s string) *pointerSplitter {
+	return &pointerSplitter{s: s, index: -1}
+}
+
+// HasMore returns true if the iterated pointer has more fields.
+func (s *pointerSplitter) HasMore() bool {
+	return s.scanned < len(s.s)
+}
+
+// Next returns the next unescaped field of the iterated pointer and the
+// position of the field in the pointer (or an empty string and -1 if the
+// pointer does not have more fields).
+func (s *pointerSplitter) Next() (string, int) {
+	if !s.HasMore() {
+		return "", -1
+	}
+
+	start := s.scanned + 1 // Skip slash
+	end := strings.IndexByte(s.s[start:], '/')
+	if end < 0 {
+		end = len(s.s)
+	} else {
+		end += start
+	}
+
+	s.index++
+	s.scanned = end
+	s.fieldEnd = end
+	return unescapePointer(s.s[start:end]), s.index
+}
+
+// Parsed returns the prefix of the iterated pointer up to the end of the last
+// field returned by Next().
+func (s *pointerSplitter) Parsed() string {
+	return s.s[:s.fieldEnd]
+}
+
+// Remaining returns the suffix of the iterated pointer that has not been
+// returned by Next() yet, which is also a valid pointer.
+func (s *pointerSplitter) Remaining() string {
+	return s.s[s.scanned:]
+}
+
+// CountRemaining returns the number of remaining fields in the pointer.
+func (s *pointerSplitter) CountRemaining() int {
+	return strings.Count(s.s[s.scanned:], "/")
+}
+
+// validPointer returns true if s is a valid JSON Pointer: an empty string or a
+// string starting with "/" where every "~" is followed by "0" or "1".
+func validPointer(s string) bool {
+	if s != "" && s[0] != '/' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '~' && (i+1 == len(s) || (s[i+1] != '0' && s[i+1] != '1')) {
+			return false
+		}
+	}
+	return true
+}
+
+// unescapePointer returns the JSON Pointer field with "~1" and "~0" replaced by
+// "/" and "~", respectively.
+func unescapePointer(field string) string {
+	if strings.IndexByte(field, '~') < 0 {
+		return field
+	}
+	field = strings.Replace(field, "~1", "/", -1)
+	return strings.Replace(field, "~0", "~", -1)
+}
+
+// escapePointer returns the JSON Pointer field with "~" and "/" replaced by
+// "~0" and "~1", respectively.
+func escapePointer(field string) string {
+	field = strings.Replace(field, "~", "~0", -1)
+	return strings.Replace(field, "/", "~1", -1)
+}
+
+// pointerIndex returns the slice index of the given JSON Pointer field. The
+// index must be a non-negative decimal number without leading zeros. The
+// field "-" (the position after the last element) returns
+// ErrIndexOutOfRange.
+func pointerIndex(field string) (int, error) {
+	if field == "-" {
+		return -1, ErrIndexOutOfRange
+	}
+	if field == "" || field[0] < '0' || field[0] > '9' || (field[0] == '0' && len(field) > 1) {
+		return -1, ErrInvalidIndex
+	}
+	index, err := strconv.Atoi(field)
+	if err != nil {
+		return -1, ErrInvalidIndex
+	}
+	return index, nil
+}
+
+// appendPointer returns the pointer to the parent of the given JSON Pointer
+// and true if its last field is "-".
+func appendPointer(pointer string) (string, bool) {
+	if !strings.HasSuffix(pointer, "/-") {
+		return "", false
+	}
+	return pointer[:len(pointer)-2], true
+}
+
+// appendValue appends the new value to the slice referenced by the given
+// attribute. If the value is Zero or Delete, the zero value of the slice
+// element type is appended. It returns false if the attribute does not
+// reference a slice, or a raw JSON document holding an array.
+func (d *Dipper) appendValue(obj interface{}, attribute string, new interface{}) (bool, error) {
+	current := d.Get(obj, attribute)
+	if err := Error(current); err != nil {
+		return true, err
+	}
+
+	if raw := d.getRawJSON(reflect.ValueOf(current)); raw.IsValid() {
+		return d.appendRawJSON(obj, attribute, current, raw, new)
+	}
+
+	slice := getElemSafe(reflect.ValueOf(current))
+	if slice.Kind() != reflect.Slice {
+		return false, nil
+	}
+	elemType := slice.Type().Elem()
+
+	var elem reflect.Value
+	if new != Zero && new != Delete {
+		elem = reflect.ValueOf(new)
+		if elem.Kind() == reflect.Ptr && !elem.Type().AssignableTo(elemType) {
+			elem = elem.Elem()
+		}
+	}
+	if !elem.IsValid() {
+		elem = reflect.Zero(elemType)
+	}
+	if !elem.Type().AssignableTo(elemType) {
+		return true, ErrTypesDoNotMatch
+	}
+
+	return true, d.Set(obj, attribute, reflect.Append(slice, elem).Interface())
+}
+
+// appendRawJSON appends the new value to the array held by the raw JSON
+// document referenced by the given attribute, and sets the re-encoded document
+// back. It returns false if the document does not hold an array.
+func (d *Dipper) appendRawJSON(obj interface{}, attribute string, current interface{}, raw reflect.Value, new interface{}) (bool, error) {
+	doc, err := decodeRawJSON(raw, attribute)
+	if err != nil {
+		return true, err
+	}
+	array, ok := doc.Interface().([]interface{})
+	if !ok {
+		return false, nil
+	}
+
+	if new == Zero || new == Delete {
+		new = nil
+	}
+	updated, err := rawJSONValue(reflect.ValueOf(current), raw, append(array, new), attribute)
+	if err != nil {
+		return true, err
+	}
+	return true, d.Set(obj, attribute, updated)
+}
@@ -0,0 +1,187 @@
+package dipper_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestDipper_Get_JSONPointer(t *testing.T) {
+	obj := map[string]interface{}{
+		"book": getTestStruct(),
+		"a/b":  "slash",
+		"m~n":  "tilde",
+		"":     "empty",
+		"-":    "dash",
+		"list": []interface{}{"zero", map[string]interface{}{"x.y": 1}},
+	}
+
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		attribute string
+		want      interface{}
+	}{
+		{name: "root", attribute: "", want: obj},
+		{name: "struct field", attribute: "/book/Author/Name", want: "Umberto Eco"},
+		{name: "slice element", attribute: "/book/Genres/1/Name", want: "Crime"},
+		{name: "key with separator", attribute: "/list/1/x.y", want: 1},
+		{name: "escaped slash", attribute: "/a~1b", want: "slash"},
+		{name: "escaped tilde", attribute: "/m~0n", want: "tilde"},
+		{name: "empty key", attribute: "/", want: "empty"},
+		{name: "dash key", attribute: "/-", want: "dash"},
+		{
+			name:      "tag names",
+			opts:      dipper.Options{TagName: "json"},
+			attribute: "/book/genres/0/name",
+			want:      "Mystery",
+		},
+		{name: "end of slice", attribute: "/list/-", want: dipper.ErrIndexOutOfRange},
+		{name: "index out of range", attribute: "/list/2", want: dipper.ErrIndexOutOfRange},
+		{name: "leading zero", attribute: "/list/01", want: dipper.ErrInvalidIndex},
+		{name: "signed index", attribute: "/list/+1", want: dipper.ErrInvalidIndex},
+		{name: "brackets are not indexes", attribute: "/list/[1]", want: dipper.ErrInvalidIndex},
+		{name: "methods are not called", attribute: "/book/Author/BirthDate/Year()", want: dipper.ErrNotFound},
+		{name: "missing slash", attribute: "book", want: dipper.ErrInvalidPointer},
+		{name: "invalid escape", attribute: "/a~2b", want: dipper.ErrInvalidPointer},
+		{name: "trailing tilde", attribute: "/a~", want: dipper.ErrInvalidPointer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Syntax = dipper.JSONPointer
+			d := dipper.New(tt.opts)
+			if got := d.Get(obj, tt.attribute); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_Set_JSONPointer(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       interface{}
+		attribute string
+		value     interface{}
+		want      interface{}
+		wantErr   error
+	}{
+		{
+			name:      "struct field",
+			obj:       &Book{Author: Author{Name: "Umberto"}},
+			attribute: "/Author/Name",
+			value:     "Umberto Eco",
+			want:      &Book{Author: Author{Name: "Umberto Eco"}},
+		},
+		{
+			name:      "map key with escaping",
+			obj:       map[string]interface{}{"a/b": 1},
+			attribute: "/a~1b",
+			value:     2,
+			want:      map[string]interface{}{"a/b": 2},
+		},
+		{
+			name:      "append to struct slice",
+			obj:       &Book{GenreNames: []string{"Mystery"}},
+			attribute: "/GenreNames/-",
+			value:     "Crime",
+			want:      &Book{GenreNames: []string{"Mystery", "Crime"}},
+		},
+		{
+			name:      "append to nil slice",
+			obj:       &Book{},
+			attribute: "/Genres/-",
+			value:     Genre{Name: "Crime"},
+			want:      &Book{Genres: []Genre{{Name: "Crime"}}},
+		},
+		{
+			name:      "append to slice in map",
+			obj:       map[string]interface{}{"list": []interface{}{1}},
+			attribute: "/list/-",
+			value:     "two",
+			want:      map[string]interface{}{"list": []interface{}{1, "two"}},
+		},
+		{
+			name:      "append zero value",
+			obj:       &[]int{1},
+			attribute: "/-",
+			value:     dipper.Zero,
+			want:      &[]int{1, 0},
+		},
+		{
+			name:      "dash key in map",
+			obj:       map[string]interface{}{"a": map[string]int{}},
+			attribute: "/a/-",
+			value:     1,
+			want:      map[string]interface{}{"a": map[string]int{"-": 1}},
+		},
+		{
+			name:      "append with wrong type",
+			obj:       &Book{GenreNames: []string{"Mystery"}},
+			attribute: "/GenreNames/-",
+			value:     1,
+			want:      &Book{GenreNames: []string{"Mystery"}},
+			wantErr:   dipper.ErrTypesDoNotMatch,
+		},
+		{
+			name:      "field after end of slice",
+			obj:       &Book{Genres: []Genre{{}}},
+			attribute: "/Genres/-/Name",
+			value:     "Crime",
+			want:      &Book{Genres: []Genre{{}}},
+			wantErr:   dipper.ErrIndexOutOfRange,
+		},
+		{
+			name:      "invalid pointer",
+			obj:       &Book{},
+			attribute: "Title",
+			value:     "1984",
+			want:      &Book{},
+			wantErr:   dipper.ErrInvalidPointer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{Syntax: dipper.JSONPointer})
+			err := d.Set(tt.obj, tt.attribute, tt.value)
+			if err != tt.wantErr {
+				t.Fatalf("Set() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.obj, tt.want) {
+				t.Errorf("Set() obj = %v, want %v", tt.obj, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_JSON_JSONPointer(t *testing.T) {
+	d := dipper.New(dipper.Options{Syntax: dipper.JSONPointer})
+	data := []byte(`{"a/b": {"list": [1, 2]}, "c.d": true}`)
+
+	if got := d.GetJSON(data, "/a~1b/list/1"); got != 2.0 {
+		t.Errorf("GetJSON() = %v, want 2", got)
+	}
+	if got := d.GetJSON(data, "/c.d"); got != true {
+		t.Errorf("GetJSON() = %v, want true", got)
+	}
+	if got := d.GetJSON(data, "/a~1b/list/-"); got != dipper.ErrIndexOutOfRange {
+		t.Errorf("GetJSON() = %v, want %v", got, dipper.ErrIndexOutOfRange)
+	}
+
+	got, err := d.SetJSON(data, "/a~1b/list/-", 3)
+	if want := `{"a/b": {"list": [1, 2, 3]}, "c.d": true}`; err != nil || string(got) != want {
+		t.Errorf("SetJSON() = %s, %v, want %s", got, err, want)
+	}
+	got, err = d.SetJSON([]byte(`{"list": []}`), "/list/-", "first")
+	if want := `{"list": ["first"]}`; err != nil || string(got) != want {
+		t.Errorf("SetJSON() = %s, %v, want %s", got, err, want)
+	}
+	got, err = d.DeleteJSON(data, "/c.d")
+	if want := `{"a/b": {"list": [1, 2]}}`; err != nil || string(got) != want {
+		t.Errorf("DeleteJSON() = %s, %v, want %s", got, err, want)
+	}
+	if _, err := d.SetJSON(data, "c.d", 1); err != dipper.ErrInvalidPointer {
+		t.Errorf("SetJSON() error = %v, want %v", err, dipper.ErrInvalidPointer)
+	}
+}
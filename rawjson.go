@@ -0,0 +1,83 @@
+package dipper
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	bytesType      = reflect.TypeOf([]byte{})
+)
+
+// getRawJSON returns the underlying value of the given value if it holds a
+// JSON document that can be traversed: a json.RawMessage, or a []byte if the
+// JSONBytes option is enabled. Otherwise, it returns an invalid reflect.Value.
+func (d *Dipper) getRawJSON(value reflect.Value) reflect.Value {
+	value = getElemSafe(value)
+	if !value.IsValid() {
+		return reflect.Value{}
+	}
+
+	t := value.Type()
+	if t == rawMessageType || (d.opts.JSONBytes && t == bytesType) {
+		return value
+	}
+	return reflect.Value{}
+}
+
+// decodeRawJSON decodes the JSON document held by raw and returns it as an
+// addressable interface{} value. An empty document is decoded as null.
+// Numbers are decoded as json.Number, so they are encoded back as they were
+// when the document is modified.
+func decodeRawJSON(raw reflect.Value, path string) (reflect.Value, error) {
+	var doc interface{}
+	if raw.Len() > 0 {
+		if err := unmarshalUseNumber(raw.Bytes(), &doc); err != nil {
+			return reflect.Value{}, &PathError{Path: path, Err: err}
+		}
+	}
+	return reflect.ValueOf(&doc).Elem(), nil
+}
+
+// unmarshalUseNumber works as json.Unmarshal(), but decodes the numbers held
+// by interface{} values as json.Number.
+func unmarshalUseNumber(data []byte, v interface{}) error {
+	if !json.Valid(data) {
+		// Get the same syntax error json.Unmarshal() would return
+		return json.Unmarshal(data, v)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// encodeRawJSON returns a function that encodes the given document back into
+// raw, to be called once the document has been modified.
+func encodeRawJSON(raw, doc reflect.Value, path string) func() error {
+	return func() error {
+		bytes, err := json.Marshal(doc.Interface())
+		if err != nil {
+			return &PathError{Path: path, Err: err}
+		}
+		raw.SetBytes(bytes)
+		return nil
+	}
+}
+
+// rawJSONValue encodes the given document as a new value of the type of the raw
+// JSON value held by current, which is returned as a pointer if current is a
+// pointer too.
+func rawJSONValue(current, raw reflect.Value, doc interface{}, path string) (interface{}, error) {
+	bytes, err := json.Marshal(doc)
+	if err != nil {
+		return nil, &PathError{Path: path, Err: err}
+	}
+	value := reflect.New(raw.Type())
+	value.Elem().SetBytes(bytes)
+	if current.Kind() != reflect.Ptr {
+		value = value.Elem()
+	}
+	return value.Interface(), nil
+}
@@ -0,0 +1,297 @@
+package dipper_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+type Event struct {
+	Type    string
+	Payload json.RawMessage
+	Data    []byte
+	Extra   *json.RawMessage
+}
+
+func getTestEvent() *Event {
+	extra := json.RawMessage(`[1, 2, 3]`)
+	return &Event{
+		Type:    "user.created",
+		Payload: json.RawMessage(`{"user": {"id": 7, "name": "Umberto", "tags": ["a", "b"]}}`),
+		Data:    []byte(`{"source": "api"}`),
+		Extra:   &extra,
+	}
+}
+
+func TestDipper_GetRawJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "raw message",
+			obj:       getTestEvent(),
+			attribute: "Payload",
+			want:      json.RawMessage(`{"user": {"id": 7, "name": "Umberto", "tags": ["a", "b"]}}`),
+		},
+		{
+			name:      "nested value",
+			obj:       getTestEvent(),
+			attribute: "Payload.user.id",
+			want:      json.Number("7"),
+		},
+		{
+			name:      "nested slice element with filter",
+			obj:       getTestEvent(),
+			attribute: "Payload.user.tags[='b']",
+			want:      "b",
+		},
+		{
+			name:      "raw message pointer",
+			obj:       getTestEvent(),
+			attribute: "Extra[2]",
+			want:      json.Number("3"),
+		},
+		{
+			name:      "filter by number",
+			obj:       json.RawMessage(`[{"id": 1, "name": "a"}, {"id": 2.5, "name": "b"}]`),
+			attribute: "[id=2.5].name",
+			want:      "b",
+		},
+		{
+			name:      "not found",
+			obj:       getTestEvent(),
+			attribute: "Payload.user.email",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "bytes are not decoded by default",
+			obj:       getTestEvent(),
+			attribute: "Data.source",
+			want:      dipper.ErrInvalidIndex,
+		},
+		{
+			name:      "bytes with JSONBytes option",
+			opts:      dipper.Options{JSONBytes: true},
+			obj:       getTestEvent(),
+			attribute: "Data.source",
+			want:      "api",
+		},
+		{
+			name:      "empty raw message",
+			obj:       &Event{},
+			attribute: "Payload.user",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "root raw message",
+			obj:       json.RawMessage(`{"a": [true]}`),
+			attribute: "a.0",
+			want:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(tt.opts)
+			got := d.Get(tt.obj, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_GetRawJSONNumbers(t *testing.T) {
+	event := &Event{Payload: json.RawMessage(`{"id": 12345678901234567890, "count": 3}`)}
+
+	if got, err := dipper.GetInt(event, "Payload.count"); err != nil || got != 3 {
+		t.Errorf("GetInt() = %v, %v, want 3", got, err)
+	}
+	if got, err := dipper.GetString(event, "Payload.id"); err != nil || got != "12345678901234567890" {
+		t.Errorf("GetString() = %v, %v, want 12345678901234567890", got, err)
+	}
+
+	if err := dipper.Set(event, "Payload.name", "b"); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+	want := json.RawMessage(`{"count":3,"id":12345678901234567890,"name":"b"}`)
+	if !reflect.DeepEqual(event.Payload, want) {
+		t.Errorf("Set() => Payload = %s, want %s", event.Payload, want)
+	}
+}
+
+func TestDipper_GetInvalidRawJSON(t *testing.T) {
+	event := &Event{Payload: json.RawMessage(`{"user": `)}
+
+	got := dipper.Get(event, "Payload.user")
+	err, ok := got.(*dipper.PathError)
+	if !ok {
+		t.Fatalf("Get() = %v, want *PathError", got)
+	}
+	if err.Path != "Payload" {
+		t.Errorf("Get() => Path = %v, want %v", err.Path, "Payload")
+	}
+}
+
+func TestDipper_SetRawJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		attribute string
+		newValue  interface{}
+		wantErr   error
+		want      *Event
+	}{
+		{
+			name:      "nested value",
+			attribute: "Payload.user.id",
+			newValue:  8,
+			want: &Event{
+				Payload: json.RawMessage(`{"user":{"id":8,"name":"Umberto","tags":["a","b"]}}`),
+			},
+		},
+		{
+			name:      "nested slice element",
+			attribute: "Payload.user.tags.0",
+			newValue:  "z",
+			want: &Event{
+				Payload: json.RawMessage(`{"user":{"id":7,"name":"Umberto","tags":["z","b"]}}`),
+			},
+		},
+		{
+			name:      "delete key",
+			attribute: "Payload.user.tags",
+			newValue:  dipper.Delete,
+			want: &Event{
+				Payload: json.RawMessage(`{"user":{"id":7,"name":"Umberto"}}`),
+			},
+		},
+		{
+			name:      "raw message pointer",
+			attribute: "Extra.1",
+			newValue:  "two",
+			want: &Event{
+				Extra: rawMessagePtr(`[1,"two",3]`),
+			},
+		},
+		{
+			name:      "bytes with JSONBytes option",
+			opts:      dipper.Options{JSONBytes: true},
+			attribute: "Data.source",
+			newValue:  "cli",
+			want: &Event{
+				Data: []byte(`{"source":"cli"}`),
+			},
+		},
+		{
+			name:      "append to raw message array",
+			opts:      dipper.Options{Syntax: dipper.JSONPointer},
+			attribute: "/Extra/-",
+			newValue:  4,
+			want: &Event{
+				Extra: rawMessagePtr(`[1,2,3,4]`),
+			},
+		},
+		{
+			name:      "append zero value to raw message array",
+			opts:      dipper.Options{Syntax: dipper.JSONPointer},
+			attribute: "/Extra/-",
+			newValue:  dipper.Zero,
+			want: &Event{
+				Extra: rawMessagePtr(`[1,2,3,null]`),
+			},
+		},
+		{
+			name:      "append to nested array of raw message",
+			opts:      dipper.Options{Syntax: dipper.JSONPointer},
+			attribute: "/Payload/user/tags/-",
+			newValue:  "c",
+			want: &Event{
+				Payload: json.RawMessage(`{"user":{"id":7,"name":"Umberto","tags":["a","b","c"]}}`),
+			},
+		},
+		{
+			name:      "dash key of raw message object",
+			opts:      dipper.Options{Syntax: dipper.JSONPointer, JSONBytes: true},
+			attribute: "/Data/-",
+			newValue:  1,
+			want: &Event{
+				Data: []byte(`{"-":1,"source":"api"}`),
+			},
+		},
+		{
+			name:      "failed set does not modify the raw message",
+			attribute: "Payload.user.tags.5",
+			newValue:  "z",
+			wantErr:   dipper.ErrIndexOutOfRange,
+			want: &Event{
+				Payload: getTestEvent().Payload,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(tt.opts)
+			event := getTestEvent()
+			err := d.Set(event, tt.attribute, tt.newValue)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("Set() = %v, want %v", err, tt.wantErr)
+			}
+
+			got := reflect.ValueOf(event).Elem()
+			want := reflect.ValueOf(tt.want).Elem()
+			for i := 0; i < want.NumField(); i++ {
+				if want.Field(i).IsZero() {
+					continue
+				}
+				if !reflect.DeepEqual(got.Field(i).Interface(), want.Field(i).Interface()) {
+					t.Errorf("Set() => %s = %s, want %s", want.Type().Field(i).Name,
+						got.Field(i).Interface(), want.Field(i).Interface())
+				}
+			}
+		})
+	}
+}
+
+func TestDipper_SetRawJSONInMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "raw message map",
+			obj:       &struct{ M map[string]json.RawMessage }{M: map[string]json.RawMessage{"k": json.RawMessage(`{"a": 1}`)}},
+			attribute: "M.k.a",
+			want:      json.RawMessage(`{"a":2}`),
+		},
+		{
+			name:      "raw message in interface map",
+			obj:       map[string]interface{}{"k": json.RawMessage(`{"a": 1}`)},
+			attribute: "k.a",
+			want:      json.RawMessage(`{"a":2}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := dipper.Set(tt.obj, tt.attribute, 2); err != nil {
+				t.Fatalf("Set() = %v", err)
+			}
+			parent := tt.attribute[:len(tt.attribute)-len(".a")]
+			if got := dipper.Get(tt.obj, parent); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Set() => Get() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func rawMessagePtr(s string) *json.RawMessage {
+	raw := json.RawMessage(s)
+	return &raw
+}
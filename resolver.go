@@ -0,0 +1,97 @@
+package dipper
+
+import (
+	"reflect"
+)
+
+// Resolver is the interface implemented by types that give access to the
+// children of custom container types, which are otherwise treated as opaque
+// values (e.g. *list.List or ordered map implementations).
+// Resolvers are registered per type using the Resolvers option.
+type Resolver interface {
+	// Get returns the child of value accessed by name. It should return
+	// ErrNotFound if there is no such child.
+	Get(value reflect.Value, name string) (reflect.Value, error)
+	// Set sets the child of value accessed by name to newValue. newValue is
+	// the value given to Dipper.Set(), so it can also be Zero or Delete.
+	Set(value reflect.Value, name string, newValue interface{}) error
+	// Keys returns the names of the children of value.
+	Keys(value reflect.Value) ([]string, error)
+}
+
+// Container is the interface implemented by types that give access to their
+// own children. Types implementing Container are accessed as custom
+// containers without registering a Resolver.
+type Container interface {
+	// GetChild returns the child accessed by name. It should return
+	// ErrNotFound if there is no such child.
+	GetChild(name string) (interface{}, error)
+	// SetChild sets the child accessed by name to value. value is the value
+	// given to Dipper.Set(), so it can also be Zero or Delete.
+	SetChild(name string, value interface{}) error
+	// ChildKeys returns the names of the children.
+	ChildKeys() []string
+}
+
+var containerType = reflect.TypeOf((*Container)(nil)).Elem()
+
+// containerResolver is the Resolver used for types implementing Container.
+type containerResolver struct{}
+
+func (containerResolver) Get(value reflect.Value, name string) (reflect.Value, error) {
+	child, err := value.Interface().(Container).GetChild(name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(&child).Elem(), nil
+}
+
+func (containerResolver) Set(value reflect.Value, name string, newValue interface{}) error {
+	return value.Interface().(Container).SetChild(name, newValue)
+}
+
+func (containerResolver) Keys(value reflect.Value) ([]string, error) {
+	return value.Interface().(Container).ChildKeys(), nil
+}
+
+// getResolver returns the Resolver for the given value and the value that must
+// be passed to it, which can be the given value or the value it points to.
+// It returns a nil Resolver if the value is not a custom container.
+func (d *Dipper) getResolver(value reflect.Value) (Resolver, reflect.Value) {
+	for value.IsValid() {
+		kind := value.Kind()
+		if (kind == reflect.Ptr || kind == reflect.Interface) && value.IsNil() {
+			break
+		}
+
+		if r, ok := d.opts.Resolvers[value.Type()]; ok {
+			return r, value
+		}
+		if r, ok := builtinResolvers[value.Type()]; ok {
+			return r, value
+		}
+
+		if kind != reflect.Interface && value.CanInterface() && value.Type().Implements(containerType) {
+			return containerResolver{}, value
+		}
+
+		if kind != reflect.Ptr && kind != reflect.Interface {
+			if value.CanAddr() && reflect.PtrTo(value.Type()).Implements(containerType) {
+				return containerResolver{}, value.Addr()
+			}
+			break
+		}
+
+		value = value.Elem()
+	}
+	return nil, reflect.Value{}
+}
+
+// pathError returns the given error wrapped in a *PathError with the given
+// path, unless it is nil or it is already an error of this package.
+func pathError(err error, path string) error {
+	if err == nil || IsFieldError(err) {
+		return err
+	}
+	return &PathError{Path: path, Err: err}
+}
@@ -0,0 +1,271 @@
+package dipper_test
+
+import (
+	"container/list"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+var errReadOnly = errors.New("list is read-only")
+
+// listResolver is a Resolver for *list.List, whose elements are accessed by
+// their position.
+type listResolver struct{}
+
+func (listResolver) element(value reflect.Value, name string) (*list.Element, error) {
+	i, err := strconv.Atoi(name)
+	if err != nil {
+		return nil, dipper.ErrInvalidIndex
+	}
+	l := value.Interface().(*list.List)
+	if i < 0 || i >= l.Len() {
+		return nil, dipper.ErrIndexOutOfRange
+	}
+	e := l.Front()
+	for ; i > 0; i-- {
+		e = e.Next()
+	}
+	return e, nil
+}
+
+func (r listResolver) Get(value reflect.Value, name string) (reflect.Value, error) {
+	e, err := r.element(value, name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(e.Value), nil
+}
+
+func (r listResolver) Set(value reflect.Value, name string, newValue interface{}) error {
+	if name == "readonly" {
+		return errReadOnly
+	}
+	e, err := r.element(value, name)
+	if err != nil {
+		return err
+	}
+	if newValue == dipper.Delete {
+		value.Interface().(*list.List).Remove(e)
+		return nil
+	}
+	e.Value = newValue
+	return nil
+}
+
+func (listResolver) Keys(value reflect.Value) ([]string, error) {
+	keys := make([]string, value.Interface().(*list.List).Len())
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	return keys, nil
+}
+
+// OrderedMap is a map that keeps the insertion order of its keys and
+// implements dipper.Container.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func NewOrderedMap(kv ...interface{}) *OrderedMap {
+	m := &OrderedMap{values: map[string]interface{}{}}
+	for i := 0; i < len(kv); i += 2 {
+		_ = m.SetChild(kv[i].(string), kv[i+1])
+	}
+	return m
+}
+
+func (m *OrderedMap) GetChild(name string) (interface{}, error) {
+	v, ok := m.values[name]
+	if !ok {
+		return nil, dipper.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *OrderedMap) SetChild(name string, value interface{}) error {
+	if value == dipper.Delete || value == dipper.Zero {
+		for i, k := range m.keys {
+			if k == name {
+				m.keys = append(m.keys[:i], m.keys[i+1:]...)
+				delete(m.values, name)
+			}
+		}
+		return nil
+	}
+	if _, ok := m.values[name]; !ok {
+		m.keys = append(m.keys, name)
+	}
+	m.values[name] = value
+	return nil
+}
+
+func (m *OrderedMap) ChildKeys() []string {
+	return m.keys
+}
+
+type Shelf struct {
+	Books    *list.List
+	Metadata OrderedMap
+	Extra    interface{}
+}
+
+func getTestShelf() *Shelf {
+	books := list.New()
+	books.PushBack(getTestStruct())
+	books.PushBack("Baudolino")
+
+	return &Shelf{
+		Books:    books,
+		Metadata: *NewOrderedMap("owner", "Umberto", "tags", []string{"novel", "mystery"}),
+		Extra:    NewOrderedMap("nested", NewOrderedMap("value", 1)),
+	}
+}
+
+func TestDipper_GetWithResolvers(t *testing.T) {
+	tests := []struct {
+		name      string
+		obj       interface{}
+		attribute string
+		want      interface{}
+	}{
+		{
+			name:      "registered resolver",
+			obj:       getTestShelf(),
+			attribute: "Books.1",
+			want:      "Baudolino",
+		},
+		{
+			name:      "nested value from registered resolver",
+			obj:       getTestShelf(),
+			attribute: "Books.0.Author.Name",
+			want:      "Umberto Eco",
+		},
+		{
+			name:      "registered resolver error",
+			obj:       getTestShelf(),
+			attribute: "Books.2",
+			want:      dipper.ErrIndexOutOfRange,
+		},
+		{
+			name:      "container with pointer receiver",
+			obj:       getTestShelf(),
+			attribute: "Metadata.tags.1",
+			want:      "mystery",
+		},
+		{
+			name:      "nested containers",
+			obj:       getTestShelf(),
+			attribute: "Extra.nested.value",
+			want:      1,
+		},
+		{
+			name:      "container child not found",
+			obj:       getTestShelf(),
+			attribute: "Extra.foo",
+			want:      dipper.ErrNotFound,
+		},
+		{
+			name:      "root container",
+			obj:       NewOrderedMap("foo", nil),
+			attribute: "foo",
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{
+				Resolvers: map[reflect.Type]dipper.Resolver{
+					reflect.TypeOf(list.New()): listResolver{},
+				},
+			})
+			got := d.Get(tt.obj, tt.attribute)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_SetWithResolvers(t *testing.T) {
+	tests := []struct {
+		name      string
+		attribute string
+		newValue  interface{}
+		wantErr   error
+		check     string
+		want      interface{}
+	}{
+		{
+			name:      "registered resolver",
+			attribute: "Books.1",
+			newValue:  "Il pendolo di Foucault",
+			check:     "Books.1",
+			want:      "Il pendolo di Foucault",
+		},
+		{
+			name:      "registered resolver delete",
+			attribute: "Books.0",
+			newValue:  dipper.Delete,
+			check:     "Books.0",
+			want:      "Baudolino",
+		},
+		{
+			name:      "nested value from registered resolver",
+			attribute: "Books.0.Title",
+			newValue:  "Il nome della rosa",
+			check:     "Books.0.Title",
+			want:      "Il nome della rosa",
+		},
+		{
+			name:      "registered resolver error",
+			attribute: "Books.readonly",
+			newValue:  "foo",
+			wantErr:   &dipper.PathError{Path: "Books.readonly", Err: errReadOnly},
+		},
+		{
+			name:      "container",
+			attribute: "Metadata.owner",
+			newValue:  "Jorge Luis",
+			check:     "Metadata.owner",
+			want:      "Jorge Luis",
+		},
+		{
+			name:      "container new child",
+			attribute: "Extra.nested.other",
+			newValue:  2,
+			check:     "Extra.nested.other",
+			want:      2,
+		},
+		{
+			name:      "container delete",
+			attribute: "Metadata.owner",
+			newValue:  dipper.Delete,
+			check:     "Metadata.owner",
+			want:      dipper.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := dipper.New(dipper.Options{
+				Resolvers: map[reflect.Type]dipper.Resolver{
+					reflect.TypeOf(list.New()): listResolver{},
+				},
+			})
+			shelf := getTestShelf()
+			err := d.Set(shelf, tt.attribute, tt.newValue)
+			if !reflect.DeepEqual(err, tt.wantErr) {
+				t.Fatalf("Set() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.check != "" {
+				if got := d.Get(shelf, tt.check); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Set() => Get() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
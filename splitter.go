@@ -8,6 +8,7 @@ type attributeSplitter struct {
 	index        int
 	hasMore      bool
 	scanIndex    int
+	fieldEnd     int
 	prevBrackets bool
 }
 
@@ -57,13 +58,30 @@ func (s *attributeSplitter) Next() (string, int) {
 
 	if index == -1 {
 		s.hasMore = false
+		s.fieldEnd = len(s.s)
 		return remain, s.index + 1
 	}
 	s.index++
+	s.fieldEnd = s.scanIndex + index
 	s.scanIndex += index + separatorLength
 	return remain[:index], s.index
 }
 
+// Parsed returns the substring of the iterated string from the beginning to
+// the end of the last field returned by Next().
+func (s *attributeSplitter) Parsed() string {
+	return s.s[:s.fieldEnd]
+}
+
+// Remaining returns the substring of the iterated string that has not been
+// returned by Next() yet.
+func (s *attributeSplitter) Remaining() string {
+	if !s.hasMore {
+		return ""
+	}
+	return s.s[s.scanIndex:]
+}
+
 // CountRemaining returns the number of remaining fields in the string.
 func (s *attributeSplitter) CountRemaining() int {
 	remain := s.s[s.scanIndex:]
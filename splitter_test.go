@@ -121,3 +121,33 @@ func TestAttributeSplitter(t *testing.T) {
 		})
 	}
 }
+
+func TestAttributeSplitter_Parsed(t *testing.T) {
+	split := newAttributeSplitter("Books[1].Author.FullName()", ".")
+	want := []string{"Books", "Books[1]", "Books[1].Author", "Books[1].Author.FullName()"}
+
+	var results []string
+	for split.HasMore() {
+		split.Next()
+		results = append(results, split.Parsed())
+	}
+
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Parsed() = %v, want %v", results, want)
+	}
+}
+
+func TestAttributeSplitter_Remaining(t *testing.T) {
+	split := newAttributeSplitter("Books[1].Author->Name", "->")
+	want := []string{"[1].Author->Name", "Name", ""}
+
+	var results []string
+	for split.HasMore() {
+		split.Next()
+		results = append(results, split.Remaining())
+	}
+
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Remaining() = %v, want %v", results, want)
+	}
+}
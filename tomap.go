@@ -0,0 +1,94 @@
+package dipper
+
+import (
+	"reflect"
+)
+
+// ToMap converts obj to nested maps, using the same names used to access its
+// values in attributes: structs and maps become map[string]interface{}
+// values, and slices and arrays become []interface{} values, recursively.
+// Struct fields are named according to the Dipper options (e.g. TagName), and
+// unexported fields are only included with the AllowUnexported option.
+// Structs without exported fields (e.g. time.Time), []byte values and other
+// values are kept as they are, and pointers are converted as the values they
+// point to. Custom containers, sync.Map, atomic.Value and
+// raw JSON values are converted as the values they hold.
+// It returns nil if obj is not a struct or a map (or a pointer to one).
+// Pointer cycles are not followed: a pointer to a value that is already being
+// converted is converted to nil.
+//
+// Example:
+//
+//	m := my_dipper.ToMap(book)
+//	err := tmpl.Execute(w, m)
+func (d *Dipper) ToMap(obj interface{}) map[string]interface{} {
+	m, _ := d.toMapValue(reflect.ValueOf(obj), map[uintptr]bool{}).(map[string]interface{})
+	return m
+}
+
+// toMapValue returns the given value converted as described in ToMap().
+func (d *Dipper) toMapValue(value reflect.Value, visiting map[uintptr]bool) interface{} {
+	if p, ok := pointerOf(value); ok {
+		if visiting[p] {
+			return nil
+		}
+		visiting[p] = true
+		defer delete(visiting, p)
+	}
+
+	children, value := d.children(value)
+	elem := getElemSafe(value)
+
+	switch kind := elem.Kind(); {
+	case isNil(elem):
+	case (kind == reflect.Slice || kind == reflect.Array) && elem.Type().Elem().Kind() != reflect.Uint8:
+		s := make([]interface{}, len(children))
+		for i, child := range children {
+			s[i] = d.toMapValue(child.value, visiting)
+		}
+		return s
+
+	case len(children) > 0 || kind == reflect.Map || (kind == reflect.Struct && hasExportedFields(elem.Type())):
+		m := make(map[string]interface{}, len(children))
+		for _, child := range children {
+			m[child.name] = d.toMapValue(child.value, visiting)
+		}
+		return m
+	}
+
+	if !elem.IsValid() || !elem.CanInterface() || isNil(elem) {
+		return nil
+	}
+	return elem.Interface()
+}
+
+// FromMap sets the values of the given nested maps (e.g. the map returned by
+// Dipper.ToMap()) into target, which must be addressable, using the same
+// names used to access its values in attributes. Map keys are set into the
+// struct fields with the same name, and values are converted to the target
+// types if needed, as in Dipper.Merge(). Nil values set the target values to
+// their zero value, and slices are replaced.
+// A key with no matching struct field returns ErrNotFound. Errors are returned
+// in a *PathError with the attribute of the value that caused them.
+//
+// Example:
+//
+//	var book Book
+//	if err := my_dipper.FromMap(m, &book); err != nil {
+//	    return err
+//	}
+func (d *Dipper) FromMap(m map[string]interface{}, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ErrUnaddressable
+		}
+		value = value.Elem()
+	}
+	if !value.CanSet() && (value.Kind() != reflect.Map || value.IsNil()) {
+		return ErrUnaddressable
+	}
+
+	mg := merger{d: d, opts: MergeOptions{NilDeletes: true}, reportPaths: true}
+	return mg.merge(value, reflect.ValueOf(m), "")
+}
@@ -0,0 +1,129 @@
+package dipper_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestDipper_ToMap(t *testing.T) {
+	type secret struct {
+		User     string
+		password string
+	}
+	type node struct {
+		Name string
+		Next *node
+	}
+	cycle := &node{Name: "a"}
+	cycle.Next = cycle
+
+	tests := []struct {
+		name string
+		opts dipper.Options
+		obj  interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "struct",
+			obj:  getTestStruct(),
+			want: map[string]interface{}{
+				"Title": "El nombre de la rosa",
+				"Year":  1980,
+				"Author": map[string]interface{}{
+					"Name":      "Umberto Eco",
+					"BirthDate": mustParseDate("1932-07-05"),
+				},
+				"GenreNames": []interface{}{"Mystery", "Crime"},
+				"Genres": []interface{}{
+					map[string]interface{}{"ID": 0, "Name": "Mystery", "Description": getTestStruct().Genres[0].Description},
+					map[string]interface{}{"ID": 1, "Name": "Crime", "Description": getTestStruct().Genres[1].Description},
+				},
+				"Extra": map[string]interface{}{"foo": map[string]interface{}{"bar": 123}},
+				"Any":   nil,
+				"ISBN":  "1234567890",
+			},
+		},
+		{
+			name: "tag names and pointers",
+			opts: dipper.Options{TagName: "json"},
+			obj:  &ServerConfig{Port: 80, TLS: &TLSConfig{Cert: "cert.pem"}},
+			want: map[string]interface{}{
+				"host": "", "port": 80, "timeout": ServerConfig{}.Timeout,
+				"tls":    map[string]interface{}{"cert": "cert.pem", "key": ""},
+				"labels": nil, "routes": nil,
+			},
+		},
+		{
+			name: "unexported fields",
+			obj:  secret{User: "admin", password: "1234"},
+			want: map[string]interface{}{"User": "admin"},
+		},
+		{
+			name: "allowed unexported fields",
+			opts: dipper.Options{AllowUnexported: true},
+			obj:  secret{User: "admin", password: "1234"},
+			want: map[string]interface{}{"User": "admin", "password": "1234"},
+		},
+		{
+			name: "maps with other key types",
+			obj:  map[int][]byte{1: []byte("a")},
+			want: map[string]interface{}{"1": []byte("a")},
+		},
+		{
+			name: "cycle",
+			obj:  cycle,
+			want: map[string]interface{}{"Name": "a", "Next": nil},
+		},
+		{
+			name: "not a map",
+			obj:  []int{1},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dipper.New(tt.opts).ToMap(tt.obj)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToMap() = %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_FromMap(t *testing.T) {
+	d := dipper.New(dipper.Options{TagName: "json"})
+
+	want := &ServerConfig{
+		Host:   "localhost",
+		Port:   8080,
+		TLS:    &TLSConfig{Cert: "cert.pem"},
+		Labels: map[string]string{"env": "prod"},
+		Routes: []Route{{Path: "/", Backend: "web"}},
+	}
+	got := &ServerConfig{}
+	if err := d.FromMap(d.ToMap(want), got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromMap(ToMap()) = %#v, want %#v", got, want)
+	}
+
+	err := d.FromMap(map[string]interface{}{"routes": []interface{}{map[string]interface{}{"target": "/"}}}, got)
+	var pathErr *dipper.PathError
+	if !errors.As(err, &pathErr) || pathErr.Err != dipper.ErrNotFound || pathErr.Path != "routes.0.target" {
+		t.Errorf("FromMap() error = %v, want %v at %q", err, dipper.ErrNotFound, "routes.0.target")
+	}
+
+	insensitive := dipper.New(dipper.Options{TagName: "json", CaseInsensitive: true})
+	config := &ServerConfig{}
+	if err := insensitive.FromMap(map[string]interface{}{"HOST": "example.com"}, config); err != nil || config.Host != "example.com" {
+		t.Errorf("FromMap() = %v, %v, want host %q", config, err, "example.com")
+	}
+
+	if err := d.FromMap(map[string]interface{}{}, ServerConfig{}); err != dipper.ErrUnaddressable {
+		t.Errorf("FromMap() error = %v, want %v", err, dipper.ErrUnaddressable)
+	}
+}
@@ -0,0 +1,174 @@
+package dipper
+
+import (
+	"reflect"
+	"sort"
+)
+
+// TransformField is a Transform() spec value with a default value, used when
+// its attribute does not reference any value.
+type TransformField struct {
+	// Path is the attribute of the value.
+	Path string
+	// Default is the value used if the attribute is not found.
+	Default interface{}
+}
+
+// Transform builds a new nested map from obj, as described by the given spec.
+// The spec keys are the keys of the result, and its values can be:
+//   - an attribute (e.g. "Books.0.Title"), whose value is copied to the result;
+//   - a TransformField, to give a default value to an attribute;
+//   - a nested spec (map[string]interface{}), which builds a nested map.
+//
+// An attribute can contain wildcards ("[*]", or "*" with the JSONPointer
+// syntax) to get a slice with the values of the remaining attribute for every
+// element of a slice or map (sorted by key), e.g. "Books[*].Year".
+// If an attribute does not reference any value (ErrNotFound,
+// ErrIndexOutOfRange or ErrFilterNotFound), its default value (or nil) is
+// used. Other errors are returned in a *PathError with the attribute, and
+// ErrInvalidSpec is returned for spec values of other types.
+//
+// Example:
+//
+//	result, err := my_dipper.Transform(library, map[string]interface{}{
+//	    "title": "Books.0.Title",
+//	    "years": "Books[*].Year",
+//	    "owner": map[string]interface{}{
+//	        "name": dipper.TransformField{Path: "Owner.Name", Default: "unknown"},
+//	    },
+//	})
+func (d *Dipper) Transform(obj interface{}, spec map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(spec))
+
+	keys := make([]string, 0, len(spec))
+	for key := range spec {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		var value interface{}
+		var err error
+
+		switch s := spec[key].(type) {
+		case string:
+			value, err = d.transformField(obj, TransformField{Path: s})
+		case TransformField:
+			value, err = d.transformField(obj, s)
+		case map[string]interface{}:
+			value, err = d.Transform(obj, s)
+		default:
+			err = ErrInvalidSpec
+		}
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// TransformInto builds a new nested map from obj as Dipper.Transform() does,
+// and sets its values into target, as Dipper.FromMap() does.
+//
+// Example:
+//
+//	var summary LibrarySummary
+//	err := my_dipper.TransformInto(library, map[string]interface{}{
+//	    "Title": "Books.0.Title",
+//	    "Years": "Books[*].Year",
+//	}, &summary)
+func (d *Dipper) TransformInto(obj interface{}, spec map[string]interface{}, target interface{}) error {
+	result, err := d.Transform(obj, spec)
+	if err != nil {
+		return err
+	}
+	return d.FromMap(result, target)
+}
+
+// transformField returns the value of the given field of obj. Errors are
+// returned in a *PathError with the field attribute.
+func (d *Dipper) transformField(obj interface{}, field TransformField) (interface{}, error) {
+	value, err := d.transformPath(obj, field.Path, field.Default)
+	if err != nil {
+		if _, ok := err.(*PathError); !ok {
+			err = &PathError{Path: field.Path, Err: err}
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+// transformPath returns the value of the given obj attribute, which can
+// contain wildcards, or def if it does not reference any value.
+func (d *Dipper) transformPath(obj interface{}, attribute string, def interface{}) (interface{}, error) {
+	prefix, rest, wildcard, err := d.splitWildcard(attribute)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wildcard {
+		prefix = attribute
+	}
+	parent := d.Get(obj, prefix)
+	if err := Error(parent); err != nil {
+		if isNotFound(err) {
+			return def, nil
+		}
+		return nil, err
+	}
+	if !wildcard {
+		return parent, nil
+	}
+
+	value := getElemSafe(reflect.ValueOf(parent))
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return def, nil
+	}
+
+	children, _ := d.children(value)
+	result := make([]interface{}, 0, len(children))
+	for _, child := range children {
+		v, err := d.transformPath(child.value.Interface(), rest, def)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// splitWildcard splits the given attribute at its first wildcard field ("[*]",
+// or "*" with the JSONPointer syntax), and returns the attributes before and
+// after it. It returns false if the attribute has no wildcards.
+func (d *Dipper) splitWildcard(attribute string) (string, string, bool, error) {
+	if attribute == "" {
+		return "", "", false, nil
+	}
+
+	splitter, err := d.newSplitter(attribute)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	prefix := ""
+	for splitter.HasMore() {
+		field, _ := splitter.Next()
+		if field == d.wildcard() {
+			return prefix, splitter.Remaining(), true, nil
+		}
+		prefix = splitter.Parsed()
+	}
+	return "", "", false, nil
+}
+
+// wildcard returns the wildcard field of the Dipper syntax ("[*]", or "*" with
+// the JSONPointer syntax).
+func (d *Dipper) wildcard() string {
+	if d.opts.Syntax == JSONPointer {
+		return "*"
+	}
+	return "[*]"
+}
@@ -0,0 +1,139 @@
+package dipper_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestDipper_Transform(t *testing.T) {
+	other := getTestStruct()
+	other.Title = "Il pendolo di Foucault"
+	other.Year = 1988
+	other.Genres = nil
+	library := map[string]interface{}{
+		"Books": []*Book{getTestStruct(), other},
+		"Owner": map[string]interface{}{"Name": "Guillermo"},
+	}
+
+	tests := []struct {
+		name     string
+		opts     dipper.Options
+		spec     map[string]interface{}
+		want     map[string]interface{}
+		wantErr  error
+		wantPath string
+	}{
+		{
+			name: "attributes",
+			spec: map[string]interface{}{
+				"title":  "Books[0].Title",
+				"author": "Books.1.Author.Name",
+				"owner":  "Owner",
+			},
+			want: map[string]interface{}{
+				"title":  "El nombre de la rosa",
+				"author": "Umberto Eco",
+				"owner":  map[string]interface{}{"Name": "Guillermo"},
+			},
+		},
+		{
+			name: "wildcards",
+			spec: map[string]interface{}{
+				"years":  "Books[*].Year",
+				"genres": "Books[*].Genres[*].Name",
+				"books":  "Books[*]",
+				"owner":  "Owner[*]",
+			},
+			want: map[string]interface{}{
+				"years":  []interface{}{1980, 1988},
+				"genres": []interface{}{[]interface{}{"Mystery", "Crime"}, []interface{}{}},
+				"books":  []interface{}{getTestStruct(), other},
+				"owner":  []interface{}{"Guillermo"},
+			},
+		},
+		{
+			name: "nested output and defaults",
+			spec: map[string]interface{}{
+				"first": map[string]interface{}{
+					"title": "Books.0.Title",
+					"isbn":  "Books.0.Publication.ISBN",
+				},
+				"third":    dipper.TransformField{Path: "Books.2.Title", Default: "none"},
+				"missing":  "Owner.Address",
+				"filter":   dipper.TransformField{Path: "Books[Title='Dune'].Year", Default: 0},
+				"wildcard": dipper.TransformField{Path: "Books[*].Genres.1.Name", Default: ""},
+			},
+			want: map[string]interface{}{
+				"first":    map[string]interface{}{"title": "El nombre de la rosa", "isbn": "1234567890"},
+				"third":    "none",
+				"missing":  nil,
+				"filter":   0,
+				"wildcard": []interface{}{"Crime", ""},
+			},
+		},
+		{
+			name: "JSON pointer syntax",
+			opts: dipper.Options{Syntax: dipper.JSONPointer},
+			spec: map[string]interface{}{"titles": "/Books/*/Title"},
+			want: map[string]interface{}{"titles": []interface{}{"El nombre de la rosa", "Il pendolo di Foucault"}},
+		},
+		{
+			name:     "invalid attribute",
+			spec:     map[string]interface{}{"year": "Books.first.Year"},
+			wantErr:  dipper.ErrInvalidIndex,
+			wantPath: "Books.first.Year",
+		},
+		{
+			name:    "invalid spec",
+			spec:    map[string]interface{}{"year": 1},
+			wantErr: dipper.ErrInvalidSpec,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dipper.New(tt.opts).Transform(library, tt.spec)
+			if tt.wantPath != "" {
+				var pathErr *dipper.PathError
+				if !errors.As(err, &pathErr) || pathErr.Err != tt.wantErr || pathErr.Path != tt.wantPath {
+					t.Fatalf("Transform() error = %v, want %v at %q", err, tt.wantErr, tt.wantPath)
+				}
+			} else if err != tt.wantErr {
+				t.Fatalf("Transform() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Transform() = %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_TransformInto(t *testing.T) {
+	type summary struct {
+		Title   string
+		Years   []int
+		Authors []Author
+	}
+
+	books := []Book{*getTestStruct(), *getTestStruct()}
+	var got summary
+	err := dipper.TransformInto(books, map[string]interface{}{
+		"Title":   "[0].Title",
+		"Years":   "[*].Year",
+		"Authors": "[*].Author",
+	}, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := summary{
+		Title:   "El nombre de la rosa",
+		Years:   []int{1980, 1980},
+		Authors: []Author{getTestStruct().Author, getTestStruct().Author},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TransformInto() = %#v, want %#v", got, want)
+	}
+}
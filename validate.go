@@ -0,0 +1,315 @@
+package dipper
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Validate checks that the given attribute can resolve against values of type
+// t, without the need of a value. It returns a *PathError with the path of the
+// first field that cannot resolve and the error that Dipper.Get() would return
+// for any value (e.g. ErrNotFound for a misspelled struct field,
+// ErrInvalidIndex for a non-numeric slice index, or
+// ErrInvalidFilterExpression for a filter on a value that is not a slice).
+// Fields whose type depends on the value (e.g. interfaces, custom containers
+// or raw JSON values) cannot be checked, so any attribute is valid from them
+// on. Missing map keys and out of range slice indexes are not errors, as they
+// depend on the value too, but indexes out of the bounds of an array are.
+//
+// Example:
+//
+//	if err := my_dipper.Validate(reflect.TypeOf(Config{}), "Server.Port"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Dipper) Validate(t reflect.Type, attribute string) error {
+	_, err := d.resolveType(t, attribute, false)
+	return err
+}
+
+// ValidateSet checks that a value of type valueType can be set to the given
+// attribute of values of type t, as Dipper.Validate() does for get
+// operations. Besides the errors returned by Dipper.Validate(), it returns
+// ErrTypesDoNotMatch if the value type does not match the attribute type,
+// ErrMethodNotSettable if the attribute calls a method, and ErrUnaddressable if
+// the value cannot be set (e.g. a field of a struct held by a map).
+// A nil valueType stands for a nil value.
+//
+// Example:
+//
+//	err := my_dipper.ValidateSet(reflect.TypeOf(&Config{}), "Server.Port", reflect.TypeOf(0))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (d *Dipper) ValidateSet(t reflect.Type, attribute string, valueType reflect.Type) error {
+	if d.opts.Syntax == JSONPointer {
+		if parent, ok := appendPointer(attribute); ok {
+			resolved, err := d.resolveType(t, parent, false)
+			if err != nil {
+				return err
+			}
+			if resolved.t != nil && derefType(resolved.t).Kind() == reflect.Slice {
+				if err := assignableType(valueType, derefType(resolved.t).Elem(), true); err != nil {
+					return &PathError{Path: attribute, Err: err}
+				}
+				return nil
+			}
+		}
+	}
+
+	resolved, err := d.resolveType(t, attribute, true)
+	if err != nil {
+		return err
+	}
+	if resolved.t == nil {
+		return nil
+	}
+	if !resolved.isChild && !resolved.addressable {
+		return &PathError{Path: attribute, Err: ErrUnaddressable}
+	}
+	if err := assignableType(valueType, resolved.t, false); err != nil {
+		return &PathError{Path: attribute, Err: err}
+	}
+	return nil
+}
+
+// resolvedType is the type resolved by Dipper.resolveType().
+type resolvedType struct {
+	// t is the type of the attribute, or nil if it depends on the value
+	t reflect.Type
+	// isChild is true if the last field is a map key
+	isChild bool
+	// addressable is true if the attribute can be set
+	addressable bool
+}
+
+// resolveType returns the type referenced by the given attribute in values of
+// type t, following the same rules as Dipper.getReflectValue().
+// If toSet is true, the attribute is resolved for a set operation.
+func (d *Dipper) resolveType(t reflect.Type, attribute string, toSet bool) (resolvedType, error) {
+	// Set operations take a pointer to the object, so the root is addressable
+	resolved := resolvedType{t: t, addressable: true}
+	if attribute == "" || t == nil {
+		return resolved, nil
+	}
+
+	splitter, err := d.newSplitter(attribute)
+	if err != nil {
+		return resolved, &PathError{Path: attribute, Err: err}
+	}
+	fail := func(err error) (resolvedType, error) {
+		return resolved, &PathError{Path: splitter.Parsed(), Err: err}
+	}
+
+	for splitter.HasMore() {
+		fieldName, i := splitter.Next()
+		last := !splitter.HasMore()
+
+		if d.opts.Syntax != JSONPointer && !d.opts.DisableMethods && isMethodCall(fieldName) {
+			if method, ok := typeMethod(resolved.t, strings.TrimSuffix(fieldName, "()")); ok {
+				if toSet {
+					return fail(ErrMethodNotSettable)
+				}
+				mt := method.Type
+				if mt.NumIn() != 1 || mt.NumOut() == 0 || mt.NumOut() > 2 ||
+					(mt.NumOut() == 2 && mt.Out(1) != errorType) {
+					return fail(ErrInvalidMethod)
+				}
+				resolved = resolvedType{t: mt.Out(0)}
+				continue
+			}
+		}
+
+		if resolved.t.Kind() == reflect.Ptr {
+			resolved.addressable = true
+		}
+		resolved.t = derefType(resolved.t)
+		t := resolved.t
+
+		// The children of these values depend on the value itself
+		if t.Kind() == reflect.Interface || t == atomicValueType || t == rawMessageType ||
+			(d.opts.JSONBytes && t == bytesType) || d.isContainerType(t) {
+			return resolvedType{}, nil
+		}
+
+		switch t.Kind() {
+		case reflect.Map:
+			keyType := t.Key()
+			if keyType.Kind() == reflect.Interface {
+				found := false
+				for _, key := range interfaceMapKeys(fieldName) {
+					found = found || key.Type().AssignableTo(keyType)
+				}
+				if !found {
+					return fail(ErrMapKeyNotString)
+				}
+			} else if _, err := mapKey(keyType, fieldName); err != nil {
+				return fail(err)
+			}
+
+			if toSet && last {
+				return resolvedType{t: t.Elem(), isChild: true}, nil
+			}
+			resolved = resolvedType{t: t.Elem()}
+
+		case reflect.Struct:
+			if isFilter(fieldName) {
+				return fail(ErrInvalidFilterExpression)
+			}
+			field, err := d.typeStructField(t, fieldName, toSet)
+			if err != nil {
+				return fail(err)
+			}
+			resolved.t = field.Type
+
+		case reflect.Slice, reflect.Array:
+			elem := resolvedType{t: t.Elem(), addressable: t.Kind() == reflect.Slice || resolved.addressable}
+
+			if d.opts.Syntax == JSONPointer {
+				index, err := pointerIndex(fieldName)
+				if err != nil {
+					return fail(err)
+				}
+				if t.Kind() == reflect.Array && index >= t.Len() {
+					return fail(ErrIndexOutOfRange)
+				}
+				resolved = elem
+				break
+			}
+
+			if i == 0 && fieldName == "" {
+				break
+			}
+
+			if strings.HasPrefix(fieldName, "[") && strings.HasSuffix(fieldName, "]") {
+				fieldName = fieldName[1 : len(fieldName)-1]
+				if strings.Contains(fieldName, "=") {
+					if err := d.validateFilter(t.Elem(), fieldName); err != nil {
+						return fail(err)
+					}
+					resolved = elem
+					break
+				}
+			}
+
+			index, err := strconv.Atoi(fieldName)
+			if err != nil {
+				return fail(ErrInvalidIndex)
+			}
+			if index < 0 || (t.Kind() == reflect.Array && index >= t.Len()) {
+				return fail(ErrIndexOutOfRange)
+			}
+			resolved = elem
+
+		default:
+			if isFilter(fieldName) {
+				return fail(ErrInvalidFilterExpression)
+			}
+			return fail(ErrNotFound)
+		}
+	}
+
+	return resolved, nil
+}
+
+// typeStructField returns the field of the given struct type accessed by the
+// given name, as Dipper.getStructField() does with values.
+func (d *Dipper) typeStructField(t reflect.Type, name string, toSet bool) (reflect.StructField, error) {
+	fields := cachedStructFields(t, d.opts.TagName)
+
+	i, err := d.lookupStructField(fields, name)
+	if err != nil {
+		return reflect.StructField{}, err
+	}
+
+	field := fields.list[i]
+	if !field.exported && (!d.opts.AllowUnexported || (toSet && !d.opts.AllowUnexportedSet)) {
+		return reflect.StructField{}, ErrUnexported
+	}
+	return t.FieldByIndex(field.index), nil
+}
+
+// validateFilter checks that the given filter expression (without brackets)
+// is valid and can match elements of the given type.
+func (d *Dipper) validateFilter(elemType reflect.Type, filter string) error {
+	match := filterRegex.FindStringSubmatch(filter)
+	if match == nil {
+		return ErrInvalidFilterExpression
+	}
+
+	v := match[2]
+	quoted := strings.HasPrefix(v, "'") && strings.HasSuffix(v, "'")
+	if _, err := strconv.ParseFloat(v, 64); err != nil && !quoted && v != "true" && v != "false" && v != "null" {
+		return ErrInvalidFilterValue
+	}
+
+	filterKey := match[1]
+	elemType = derefType(elemType)
+	switch elemType.Kind() {
+	case reflect.Interface, reflect.Map:
+		return nil
+	case reflect.Struct:
+		if filterKey == "" {
+			return ErrFilterNotFound
+		}
+		_, err := d.typeStructField(elemType, filterKey, false)
+		return err
+	}
+	if filterKey != "" {
+		return ErrFilterNotFound
+	}
+	return nil
+}
+
+// isFilter returns true if the given field is a filter expression (e.g.
+// "[Name='Dune']").
+func isFilter(fieldName string) bool {
+	return strings.HasPrefix(fieldName, "[") && strings.HasSuffix(fieldName, "]") && strings.Contains(fieldName, "=")
+}
+
+// typeMethod returns the exported method of the given type with the given
+// name, looking for methods with both value and pointer receivers.
+func typeMethod(t reflect.Type, name string) (reflect.Method, bool) {
+	if t.Kind() == reflect.Interface {
+		return reflect.Method{}, false
+	}
+	if m, ok := t.MethodByName(name); ok {
+		return m, true
+	}
+	if t.Kind() != reflect.Ptr {
+		return reflect.PtrTo(t).MethodByName(name)
+	}
+	return reflect.Method{}, false
+}
+
+// derefType returns the type pointed to by the given type, following nested
+// pointers.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// assignableType returns ErrTypesDoNotMatch if a value of type valueType (or
+// nil, if valueType is nil) cannot be set to a value of type t.
+// Pointer values are dereferenced if their type is not t, as Dipper.Set() does.
+// If assignable is true, the assignability rules are used instead of requiring
+// the same type (e.g. to append slice elements).
+func assignableType(valueType, t reflect.Type, assignable bool) error {
+	if valueType == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return nil
+		}
+		return ErrTypesDoNotMatch
+	}
+
+	if valueType.Kind() == reflect.Ptr && valueType != t && !(assignable && valueType.AssignableTo(t)) {
+		valueType = valueType.Elem()
+	}
+	if valueType == t || ((assignable || t.Kind() == reflect.Interface) && valueType.AssignableTo(t)) {
+		return nil
+	}
+	return ErrTypesDoNotMatch
+}
@@ -0,0 +1,172 @@
+package dipper_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestDipper_Validate(t *testing.T) {
+	bookType := reflect.TypeOf(&Book{})
+
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		t         reflect.Type
+		attribute string
+		wantErr   error
+		wantPath  string
+	}{
+		{name: "struct field", t: bookType, attribute: "Author.Name"},
+		{name: "embedded field", t: bookType, attribute: "ISBN"},
+		{name: "slice index", t: bookType, attribute: "Genres.5.Name"},
+		{name: "slice index in brackets", t: bookType, attribute: "Genres[1].Name"},
+		{name: "filter", t: bookType, attribute: "Genres[Name='Crime'].Description"},
+		{name: "map key", t: bookType, attribute: "Extra.foo"},
+		{name: "interface", t: bookType, attribute: "Extra.foo.bar.baz"},
+		{name: "method", t: reflect.TypeOf(&Order{}), attribute: "First().Total()"},
+		{name: "root", t: bookType, attribute: ""},
+		{
+			name: "misspelled field", t: bookType, attribute: "Author.Nmae",
+			wantErr: dipper.ErrNotFound, wantPath: "Author.Nmae",
+		},
+		{
+			name: "index into a struct", t: bookType, attribute: "Author.0",
+			wantErr: dipper.ErrNotFound, wantPath: "Author.0",
+		},
+		{
+			name: "index into a scalar", t: bookType, attribute: "Title.0",
+			wantErr: dipper.ErrNotFound, wantPath: "Title.0",
+		},
+		{
+			name: "invalid index", t: bookType, attribute: "Genres.first.Name",
+			wantErr: dipper.ErrInvalidIndex, wantPath: "Genres.first",
+		},
+		{
+			name: "array index out of range", t: reflect.TypeOf([2]int{}), attribute: "2",
+			wantErr: dipper.ErrIndexOutOfRange, wantPath: "2",
+		},
+		{
+			name: "filter on a struct", t: bookType, attribute: "Author[Name='Umberto Eco']",
+			wantErr: dipper.ErrInvalidFilterExpression, wantPath: "Author[Name='Umberto Eco']",
+		},
+		{
+			name: "filter with unknown key", t: bookType, attribute: "Genres[Title='Crime']",
+			wantErr: dipper.ErrNotFound, wantPath: "Genres[Title='Crime']",
+		},
+		{
+			name: "filter with invalid value", t: bookType, attribute: "Genres[Name=Crime]",
+			wantErr: dipper.ErrInvalidFilterValue, wantPath: "Genres[Name=Crime]",
+		},
+		{
+			name: "filter with key on scalars", t: bookType, attribute: "GenreNames[Name='Crime']",
+			wantErr: dipper.ErrFilterNotFound, wantPath: "GenreNames[Name='Crime']",
+		},
+		{
+			name: "invalid map key", t: reflect.TypeOf(map[int]string{}), attribute: "one",
+			wantErr: dipper.ErrInvalidMapKey, wantPath: "one",
+		},
+		{
+			name: "invalid method", t: reflect.TypeOf(Order{}), attribute: "Count()",
+			wantErr: dipper.ErrInvalidMethod, wantPath: "Count()",
+		},
+		{
+			name: "struct without exported fields", t: bookType, attribute: "Author.BirthDate.wall",
+			wantErr: dipper.ErrUnexported, wantPath: "Author.BirthDate.wall",
+		},
+		{
+			name: "tag names", opts: dipper.Options{TagName: "json"}, t: bookType, attribute: "author.name",
+		},
+		{
+			name: "case insensitive", opts: dipper.Options{CaseInsensitive: true}, t: bookType, attribute: "author.NAME",
+		},
+		{
+			name: "JSON pointer syntax", opts: dipper.Options{Syntax: dipper.JSONPointer}, t: bookType, attribute: "/Genres/01",
+			wantErr: dipper.ErrInvalidIndex, wantPath: "/Genres/01",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dipper.New(tt.opts).Validate(tt.t, tt.attribute)
+			checkValidateError(t, err, tt.wantErr, tt.wantPath)
+		})
+	}
+}
+
+func TestDipper_ValidateSet(t *testing.T) {
+	bookType := reflect.TypeOf(&Book{})
+
+	tests := []struct {
+		name      string
+		opts      dipper.Options
+		t         reflect.Type
+		attribute string
+		valueType reflect.Type
+		wantErr   error
+		wantPath  string
+	}{
+		{name: "struct field", t: bookType, attribute: "Author.Name", valueType: reflect.TypeOf("")},
+		{name: "pointer value", t: bookType, attribute: "Year", valueType: reflect.TypeOf(intPtr(1))},
+		{name: "map value", t: bookType, attribute: "Extra.foo", valueType: reflect.TypeOf(1)},
+		{name: "interface", t: bookType, attribute: "Any", valueType: reflect.TypeOf(time.Time{})},
+		{name: "nil value", t: bookType, attribute: "Genres", valueType: nil},
+		{
+			name: "type mismatch", t: bookType, attribute: "Year", valueType: reflect.TypeOf(""),
+			wantErr: dipper.ErrTypesDoNotMatch, wantPath: "Year",
+		},
+		{
+			name: "nil to a scalar", t: bookType, attribute: "Title", valueType: nil,
+			wantErr: dipper.ErrTypesDoNotMatch, wantPath: "Title",
+		},
+		{
+			name: "map value mismatch", t: reflect.TypeOf(map[string]int{}), attribute: "a", valueType: reflect.TypeOf(""),
+			wantErr: dipper.ErrTypesDoNotMatch, wantPath: "a",
+		},
+		{
+			name: "struct held by a map", t: reflect.TypeOf(map[string]Author{}), attribute: "a.Name", valueType: reflect.TypeOf(""),
+			wantErr: dipper.ErrUnaddressable, wantPath: "a.Name",
+		},
+		{
+			name: "struct pointer held by a map", t: reflect.TypeOf(map[string]*Author{}), attribute: "a.Name", valueType: reflect.TypeOf(""),
+		},
+		{
+			name: "method", t: reflect.TypeOf(&Order{}), attribute: "First().Name", valueType: reflect.TypeOf(""),
+			wantErr: dipper.ErrMethodNotSettable, wantPath: "First()",
+		},
+		{
+			name: "append", opts: dipper.Options{Syntax: dipper.JSONPointer}, t: bookType, attribute: "/GenreNames/-",
+			valueType: reflect.TypeOf(""),
+		},
+		{
+			name: "append mismatch", opts: dipper.Options{Syntax: dipper.JSONPointer}, t: bookType, attribute: "/Genres/-",
+			valueType: reflect.TypeOf(""), wantErr: dipper.ErrTypesDoNotMatch, wantPath: "/Genres/-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := dipper.New(tt.opts).ValidateSet(tt.t, tt.attribute, tt.valueType)
+			checkValidateError(t, err, tt.wantErr, tt.wantPath)
+		})
+	}
+}
+
+func checkValidateError(t *testing.T, err, wantErr error, wantPath string) {
+	t.Helper()
+	if wantErr == nil {
+		if err != nil {
+			t.Errorf("error = %v, want nil", err)
+		}
+		return
+	}
+
+	var pathErr *dipper.PathError
+	if !errors.As(err, &pathErr) {
+		t.Fatalf("error = %v, want a *PathError", err)
+	}
+	if pathErr.Err != wantErr || pathErr.Path != wantPath {
+		t.Errorf("error = %v, want %v at %q", err, wantErr, wantPath)
+	}
+}
@@ -0,0 +1,182 @@
+package dipper
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// WalkAction is the result of a WalkFunc, which tells Dipper.Walk() how to
+// continue.
+type WalkAction int
+
+const (
+	// Continue continues walking, including the children of the current value.
+	Continue WalkAction = iota
+	// SkipChildren continues walking, but skips the children of the current
+	// value.
+	SkipChildren
+	// Stop stops walking.
+	Stop
+)
+
+// WalkFunc is the function called by Dipper.Walk() for every value, with its
+// attribute (an empty string for the root value) and its depth (0 for the root
+// value).
+type WalkFunc func(path string, v reflect.Value, depth int) WalkAction
+
+// WalkOptions defines the behavior of Dipper.Walk().
+type WalkOptions struct {
+	// MaxDepth is the maximum depth of the visited values (e.g. 1 visits the
+	// root value and its children). The default value 0 means no limit.
+	MaxDepth int
+}
+
+// Walk calls fn for obj and every value reachable from it using attributes,
+// in a deterministic order: a value is visited before its children, which are
+// visited in field order for structs, by index for slices and arrays, and
+// sorted by their string representation for map keys. Custom containers,
+// sync.Map, atomic.Value and raw JSON values are also traversed, and the value
+// passed to fn is the value held by atomic.Value and raw JSON values. Struct
+// fields are named as they are accessed in attributes (e.g. according to the
+// TagName option).
+// Pointer cycles are not followed: a pointer to a value that is already being
+// walked is not visited again.
+// Values can be modified through v if v.CanSet() is true, which is the case
+// for struct fields and array elements reached through a pointer (e.g. if obj
+// is a pointer to a struct) and for slice elements. Map values, values held by
+// interfaces, and the values of custom containers, atomic.Value and raw JSON
+// values are copies or cannot be set, so changes to them are not stored.
+//
+// Example:
+//
+//	my_dipper.Walk(&config, func(path string, v reflect.Value, depth int) dipper.WalkAction {
+//	    if strings.HasSuffix(path, "Password") && v.CanSet() {
+//	        v.SetString("***")
+//	        return dipper.SkipChildren
+//	    }
+//	    return dipper.Continue
+//	}, dipper.WalkOptions{MaxDepth: 3})
+func (d *Dipper) Walk(obj interface{}, fn WalkFunc, opts ...WalkOptions) {
+	w := walker{d: d, visiting: map[uintptr]bool{}}
+	for _, o := range opts {
+		w.maxDepth = o.MaxDepth
+	}
+	w.fn = func(path string, v reflect.Value, _ []namedValue, depth int) WalkAction {
+		return fn(path, v, depth)
+	}
+	w.walk(reflect.ValueOf(obj), "", 0)
+}
+
+// walker walks through the values reachable from a value.
+type walker struct {
+	d        *Dipper
+	maxDepth int
+	visiting map[uintptr]bool
+	// fn is called for every value, along with its children
+	fn func(path string, v reflect.Value, children []namedValue, depth int) WalkAction
+}
+
+// walk visits the given value and its children, and returns false if the walk
+// must stop.
+func (w *walker) walk(value reflect.Value, path string, depth int) bool {
+	if p, ok := pointerOf(value); ok {
+		if w.visiting[p] {
+			return true
+		}
+		w.visiting[p] = true
+		defer delete(w.visiting, p)
+	}
+
+	children, value := w.d.children(value)
+	switch w.fn(path, value, children, depth) {
+	case Stop:
+		return false
+	case SkipChildren:
+		return true
+	}
+
+	if w.maxDepth > 0 && depth >= w.maxDepth {
+		return true
+	}
+	for _, child := range children {
+		if !w.walk(child.value, w.d.joinAttribute(path, child.name), depth+1) {
+			return false
+		}
+	}
+	return true
+}
+
+// children returns the children of the given value that can be accessed with
+// attributes: the fields of a struct, the keys of a map (sorted by their
+// string representation), the elements of a slice or array (except []byte),
+// the children of a custom container, or the children of the value held by an
+// atomic.Value or a raw JSON value. It also returns the value itself, or the
+// value held by an atomic.Value or a raw JSON value.
+// It returns no children if the value is not a container or cannot be read.
+func (d *Dipper) children(value reflect.Value) ([]namedValue, reflect.Value) {
+	value, err := loadAtomicValue(value)
+	if err != nil {
+		return nil, value
+	}
+
+	if raw := d.getRawJSON(value); raw.IsValid() {
+		doc, err := decodeRawJSON(raw, "")
+		if err != nil {
+			return nil, value
+		}
+		value = doc
+	}
+
+	if r, container := d.getResolver(value); r != nil {
+		keys, err := r.Keys(container)
+		if err != nil {
+			return nil, value
+		}
+		var children []namedValue
+		for _, key := range keys {
+			if child, err := r.Get(container, key); err == nil {
+				children = append(children, namedValue{name: key, value: child})
+			}
+		}
+		return children, value
+	}
+
+	elem := getElemSafe(value)
+
+	switch elem.Kind() {
+	case reflect.Struct:
+		if hasExportedFields(elem.Type()) {
+			return d.structFieldValues(elem), value
+		}
+
+	case reflect.Map:
+		var children []namedValue
+		for _, key := range sortedMapKeys(elem) {
+			children = append(children, namedValue{name: fmt.Sprint(key.Interface()), value: elem.MapIndex(key)})
+		}
+		return children, value
+
+	case reflect.Slice, reflect.Array:
+		if elem.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		children := make([]namedValue, elem.Len())
+		for i := range children {
+			children[i] = namedValue{name: strconv.Itoa(i), value: elem.Index(i)}
+		}
+		return children, value
+	}
+
+	return nil, value
+}
+
+// pointerOf returns the address held by the given value and true if it is a
+// non-nil pointer or map, or an interface holding one.
+func pointerOf(value reflect.Value) (uintptr, bool) {
+	value = interfaceElem(value)
+	if (value.Kind() != reflect.Ptr && value.Kind() != reflect.Map) || value.IsNil() {
+		return 0, false
+	}
+	return value.Pointer(), true
+}
@@ -0,0 +1,107 @@
+package dipper_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/flusflas/dipper"
+)
+
+func TestDipper_Walk(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	cycle := &node{Name: "a"}
+	cycle.Next = cycle
+
+	tests := []struct {
+		name   string
+		opts   dipper.Options
+		obj    interface{}
+		wopts  []dipper.WalkOptions
+		action func(path string) dipper.WalkAction
+		want   []string
+	}{
+		{
+			name: "all values",
+			obj:  getTestStruct(),
+			want: []string{
+				"0 ", "1 Title", "1 Year", "1 Author", "2 Author.Name", "2 Author.BirthDate",
+				"1 GenreNames", "2 GenreNames.0", "2 GenreNames.1",
+				"1 Genres", "2 Genres.0", "3 Genres.0.ID", "3 Genres.0.Name", "3 Genres.0.Description",
+				"2 Genres.1", "3 Genres.1.ID", "3 Genres.1.Name", "3 Genres.1.Description",
+				"1 Extra", "2 Extra.foo", "3 Extra.foo.bar", "1 Any", "1 ISBN",
+			},
+		},
+		{
+			name: "sorted map keys and JSON pointer syntax",
+			opts: dipper.Options{Syntax: dipper.JSONPointer},
+			obj:  map[string]interface{}{"b": 1, "a": []int{2}, "c/d": json.RawMessage(`{"e": true}`)},
+			want: []string{"0 ", "1 /a", "2 /a/0", "1 /b", "1 /c~1d", "2 /c~1d/e"},
+		},
+		{
+			name:  "max depth",
+			obj:   getTestStruct(),
+			wopts: []dipper.WalkOptions{{MaxDepth: 1}},
+			want: []string{
+				"0 ", "1 Title", "1 Year", "1 Author", "1 GenreNames", "1 Genres", "1 Extra", "1 Any", "1 ISBN",
+			},
+		},
+		{
+			name: "skip children and stop",
+			obj:  getTestStruct(),
+			action: func(path string) dipper.WalkAction {
+				switch path {
+				case "Author", "Genres":
+					return dipper.SkipChildren
+				case "Extra":
+					return dipper.Stop
+				}
+				return dipper.Continue
+			},
+			want: []string{
+				"0 ", "1 Title", "1 Year", "1 Author", "1 GenreNames", "2 GenreNames.0", "2 GenreNames.1",
+				"1 Genres", "1 Extra",
+			},
+		},
+		{
+			name: "cycle",
+			obj:  cycle,
+			want: []string{"0 ", "1 Name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			dipper.New(tt.opts).Walk(tt.obj, func(path string, v reflect.Value, depth int) dipper.WalkAction {
+				got = append(got, fmt.Sprintf("%d %s", depth, path))
+				if tt.action != nil {
+					return tt.action(path)
+				}
+				return dipper.Continue
+			}, tt.wopts...)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Walk() visited %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDipper_Walk_SetValues(t *testing.T) {
+	config := &ServerConfig{Host: "localhost", TLS: &TLSConfig{Cert: "cert.pem", Key: "secret"}}
+
+	dipper.Walk(config, func(path string, v reflect.Value, depth int) dipper.WalkAction {
+		if path == "TLS.Key" {
+			v.SetString("***")
+		}
+		return dipper.Continue
+	})
+
+	if config.TLS.Key != "***" {
+		t.Errorf("Walk() did not set the value, got %q", config.TLS.Key)
+	}
+}